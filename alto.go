@@ -0,0 +1,114 @@
+package corpus
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+)
+
+// ALTO reads tokens from ALTO XML files. The tokens are read from
+// the CONTENT attributes of the String elements and are split in the
+// same way as DTAReadTokens splits tokens.
+type ALTO struct {
+	r io.Reader
+}
+
+// NewALTO creates a new ALTO reader that reads from the given reader.
+func NewALTO(r io.Reader) *ALTO {
+	return &ALTO{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (a *ALTO) Tokens(f func(Token)) error {
+	return a.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface.
+func (a *ALTO) TokensContext(ctx context.Context, f TokenFunc) error {
+	return a.OCRTokensContext(ctx, func(t OCRToken) error {
+		return f(t.Token)
+	})
+}
+
+// OCRTokens reads all tokens with their positions and alternatives.
+// The CONTENT of a String element is the alternative with index 0;
+// its ALTERNATIVE elements follow with the indices 1, 2, ...
+func (a *ALTO) OCRTokens(f func(OCRToken)) error {
+	return a.OCRTokensContext(context.Background(), func(t OCRToken) error {
+		f(t)
+		return nil
+	})
+}
+
+// OCRTokensContext reads all tokens with their positions and
+// alternatives. It handles cancellation and errors in the same
+// way as DTAReadTokensContext.
+func (a *ALTO) OCRTokensContext(ctx context.Context, f func(OCRToken) error) error {
+	d := xml.NewDecoder(contextReader{ctx: ctx, r: a.r})
+	var page, line string
+	var blocks []string
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapContext(ctx, err, "invalid alto file")
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			switch tt.Name.Local {
+			case "Page":
+				page, blocks, line = xmlAttr(tt, "ID"), nil, ""
+			case "TextBlock":
+				blocks = append(blocks, xmlAttr(tt, "ID"))
+			case "TextLine":
+				line = xmlAttr(tt, "ID")
+			case "String":
+				var str altoString
+				if err := d.DecodeElement(&str, &tt); err != nil {
+					return wrapContext(ctx, err, "invalid alto file")
+				}
+				w := str.ocrToken()
+				w.Page, w.Line = page, line
+				if len(blocks) > 0 {
+					w.Region = blocks[len(blocks)-1]
+				}
+				if err := tokenizeOCRWord(ctx, w, str.Content, f); err != nil {
+					return stopped(err)
+				}
+			}
+		case xml.EndElement:
+			if tt.Name.Local == "TextBlock" && len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		}
+	}
+}
+
+type altoString struct {
+	Content      string   `xml:"CONTENT,attr"`
+	WC           float64  `xml:"WC,attr"`
+	HPos         float64  `xml:"HPOS,attr"`
+	VPos         float64  `xml:"VPOS,attr"`
+	Width        float64  `xml:"WIDTH,attr"`
+	Height       float64  `xml:"HEIGHT,attr"`
+	Alternatives []string `xml:"ALTERNATIVE"`
+}
+
+func (s altoString) ocrToken() OCRToken {
+	t := OCRToken{
+		Box: Box{
+			X0: int(s.HPos),
+			Y0: int(s.VPos),
+			X1: int(s.HPos + s.Width),
+			Y1: int(s.VPos + s.Height),
+		},
+		Conf:         s.WC,
+		Alternatives: []TextEquiv{{Index: 0, Text: s.Content, Conf: s.WC}},
+	}
+	for i, alt := range s.Alternatives {
+		t.Alternatives = append(t.Alternatives, TextEquiv{Index: i + 1, Text: alt})
+	}
+	return t
+}
@@ -0,0 +1,61 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestALTOOCRTokens(t *testing.T) {
+	tests := []struct {
+		i    int
+		want OCRToken
+	}{
+		{0, OCRToken{Token: "D", Box: Box{100, 100, 160, 140}, Conf: .91, Page: "P1",
+			Region: "TB1", Line: "TL1", Alternatives: []TextEquiv{{Text: "D.", Conf: .91}}}},
+		{2, OCRToken{Token: "Henrici", Box: Box{180, 100, 400, 140}, Conf: .87, Page: "P1",
+			Region: "TB1", Line: "TL1", Alternatives: []TextEquiv{
+				{Text: "Henrici", Conf: .87}, {Index: 1, Text: "Hcnrici"}}}},
+		{3, OCRToken{Token: "Caſparis", Box: Box{100, 160, 300, 200}, Page: "P1",
+			Region: "TB2", Line: "TL2", Alternatives: []TextEquiv{{Text: "Caſparis"}}}},
+	}
+	r := openTestFile(t, "testdata/alto.xml")
+	defer r.Close()
+	var ts []OCRToken
+	if err := NewALTO(r).OCRTokens(func(t OCRToken) { ts = append(ts, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(ts) != 4 {
+		t.Fatalf("expected 4 tokens; got %d", len(ts))
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.i), func(t *testing.T) {
+			if got := ts[tc.i]; !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestALTOTokens(t *testing.T) {
+	alto := `<alto><Layout><Page><PrintSpace><TextBlock><TextLine>` +
+		`<String CONTENT="a,b"/><String CONTENT="c"/>` +
+		`</TextLine></TextBlock></PrintSpace></Page></Layout></alto>`
+	want := []Token{"a", ",", "b", "c"}
+	var tokener Tokener = NewALTO(strings.NewReader(alto))
+	var got []Token
+	if err := tokener.Tokens(func(t Token) { got = append(got, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestALTOError(t *testing.T) {
+	err := NewALTO(strings.NewReader(`<alto><String CONTENT="a"`)).Tokens(func(Token) {})
+	if err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
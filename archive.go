@@ -0,0 +1,200 @@
+package corpus
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DocumentError is the error type for errors of single documents.
+type DocumentError struct {
+	Name string // name of the document
+	Err  error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+// Cause returns the underlying error.
+func (e *DocumentError) Cause() error {
+	return e.Err
+}
+
+// DocumentFunc is the type of the callback function that is called
+// for each document. The reader is only valid during the call.
+type DocumentFunc func(name string, r io.Reader) error
+
+// EachDocument calls the callback function for each document of the
+// given input. The input is sniffed: gzip and bzip2 compressed input
+// is decompressed and each regular file of zip and tar archives is
+// reported as a document. Archives and compressed files can be nested.
+// The name of a compressed document is the name of the input without
+// its compression extension; archive members are named `archive/member`.
+// Other input is reported as one document with the given name.
+// Zip archives are read into memory; use EachDocumentInFile to avoid this.
+func EachDocument(name string, r io.Reader, f DocumentFunc) error {
+	return EachDocumentContext(context.Background(), name, r, f)
+}
+
+// EachDocumentContext calls the callback function for each document of
+// the given input. Errors of the callback function and errors that occur
+// while reading are returned as *DocumentError. It handles cancellation
+// and ErrStop in the same way as DTAReadTokensContext.
+func EachDocumentContext(ctx context.Context, name string, r io.Reader, f DocumentFunc) error {
+	return stopped(eachDocument(ctx, name, contextReader{ctx: ctx, r: r}, f))
+}
+
+// EachDocumentInFile opens the file with the given path and calls
+// the callback function for each of its documents.
+func EachDocumentInFile(path string, f DocumentFunc) error {
+	return EachDocumentInFileContext(context.Background(), path, f)
+}
+
+// EachDocumentInFileContext opens the file with the given path and calls
+// the callback function for each of its documents. Zip archives are read
+// directly from the file.
+func EachDocumentInFileContext(ctx context.Context, path string, f DocumentFunc) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return documentError(ctx, path, err)
+	}
+	defer file.Close()
+	magic := make([]byte, len(zipMagic))
+	if n, _ := file.ReadAt(magic, 0); n == len(magic) && bytes.Equal(magic, zipMagic) {
+		info, err := file.Stat()
+		if err != nil {
+			return documentError(ctx, path, err)
+		}
+		zr, err := zip.NewReader(file, info.Size())
+		if err != nil {
+			return documentError(ctx, path, err)
+		}
+		return stopped(eachZipDocument(ctx, path, zr, f))
+	}
+	return EachDocumentContext(ctx, path, file, f)
+}
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zipMagic   = []byte("PK\x03\x04")
+	tarMagic   = []byte("ustar")
+)
+
+// tarMagicOffset is the offset of the magic bytes in a tar header.
+const tarMagicOffset = 257
+
+func eachDocument(ctx context.Context, name string, r io.Reader, f DocumentFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	br := bufio.NewReader(r)
+	head, err := br.Peek(tarMagicOffset + len(tarMagic))
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return documentError(ctx, name, err)
+	}
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return documentError(ctx, name, err)
+		}
+		defer gr.Close()
+		return eachDocument(ctx, trimExt(name, ".gz", ".tgz"), gr, f)
+	case bytes.HasPrefix(head, bzip2Magic):
+		return eachDocument(ctx, trimExt(name, ".bz2", ".tbz2"), bzip2.NewReader(br), f)
+	case bytes.HasPrefix(head, zipMagic):
+		bs, err := ioutil.ReadAll(br)
+		if err != nil {
+			return documentError(ctx, name, err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(bs), int64(len(bs)))
+		if err != nil {
+			return documentError(ctx, name, err)
+		}
+		return eachZipDocument(ctx, name, zr, f)
+	case len(head) > tarMagicOffset && bytes.HasPrefix(head[tarMagicOffset:], tarMagic):
+		return eachTarDocument(ctx, name, tar.NewReader(br), f)
+	}
+	return documentError(ctx, name, f(name, br))
+}
+
+// documentError wraps the error of the named document. If the
+// context is done, the context's error is returned instead.
+// Nil, ErrStop and document errors are not wrapped.
+func documentError(ctx context.Context, name string, err error) error {
+	if err == nil || err == ErrStop {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if _, ok := err.(*DocumentError); ok {
+		return err
+	}
+	return &DocumentError{Name: name, Err: err}
+}
+
+func eachZipDocument(ctx context.Context, name string, zr *zip.Reader, f DocumentFunc) error {
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		member := name + "/" + file.Name
+		r, err := file.Open()
+		if err != nil {
+			return documentError(ctx, member, err)
+		}
+		err = eachDocument(ctx, member, r, f)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eachTarDocument(ctx context.Context, name string, tr *tar.Reader, f DocumentFunc) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return documentError(ctx, name, err)
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		if err := eachDocument(ctx, name+"/"+hdr.Name, tr, f); err != nil {
+			return err
+		}
+	}
+}
+
+// trimExt removes the first matching extension from the name.
+// The extensions .tgz and .tbz2 are replaced with .tar.
+func trimExt(name string, exts ...string) string {
+	ext := filepath.Ext(name)
+	for _, e := range exts {
+		if ext != e {
+			continue
+		}
+		if strings.HasPrefix(e, ".t") {
+			return strings.TrimSuffix(name, e) + ".tar"
+		}
+		return strings.TrimSuffix(name, e)
+	}
+	return name
+}
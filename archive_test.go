@@ -0,0 +1,211 @@
+package corpus
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type testDocument struct {
+	name, content string
+}
+
+func gzipBytes(t *testing.T, bs []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(bs); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zipBytes(t *testing.T, docs ...testDocument) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	if _, err := w.Create("dir/"); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for _, doc := range docs {
+		f, err := w.Create(doc.name)
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if _, err := f.Write([]byte(doc.content)); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, docs ...testDocument) []byte {
+	buf := &bytes.Buffer{}
+	w := tar.NewWriter(buf)
+	if err := w.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for _, doc := range docs {
+		hdr := &tar.Header{Name: doc.name, Mode: 0644, Size: int64(len(doc.content))}
+		if err := w.WriteHeader(hdr); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if _, err := w.Write([]byte(doc.content)); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readDocuments(t *testing.T, name string, bs []byte) []testDocument {
+	var docs []testDocument
+	err := EachDocument(name, bytes.NewReader(bs), func(name string, r io.Reader) error {
+		bs, err := ioutil.ReadAll(r)
+		docs = append(docs, testDocument{name, string(bs)})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return docs
+}
+
+func TestEachDocument(t *testing.T) {
+	docs := []testDocument{{"a.txt", "first"}, {"b.xml", "<second/>"}}
+	tests := []struct {
+		name  string
+		input []byte
+		want  []testDocument
+	}{
+		{"plain.txt", []byte("plain"), []testDocument{{"plain.txt", "plain"}}},
+		{"empty.txt", nil, []testDocument{{"empty.txt", ""}}},
+		{"plain.txt.gz", gzipBytes(t, []byte("plain")), []testDocument{{"plain.txt", "plain"}}},
+		{"docs.zip", zipBytes(t, docs...), []testDocument{
+			{"docs.zip/a.txt", "first"}, {"docs.zip/b.xml", "<second/>"}}},
+		{"docs.tar", tarBytes(t, docs...), []testDocument{
+			{"docs.tar/a.txt", "first"}, {"docs.tar/b.xml", "<second/>"}}},
+		{"docs.tgz", gzipBytes(t, tarBytes(t, docs...)), []testDocument{
+			{"docs.tar/a.txt", "first"}, {"docs.tar/b.xml", "<second/>"}}},
+		{"nested.zip", zipBytes(t, testDocument{"a.txt.gz", string(gzipBytes(t, []byte("first")))}),
+			[]testDocument{{"nested.zip/a.txt", "first"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := readDocuments(t, tc.name, tc.input); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEachDocumentBzip2(t *testing.T) {
+	var got []Token
+	err := EachDocumentInFile("testdata/text.txt.bz2", func(name string, r io.Reader) error {
+		if name != "testdata/text.txt" {
+			t.Fatalf("invalid name: %s", name)
+		}
+		return NewText(r).Tokens(func(t Token) { got = append(got, t) })
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := []Token{"D", ".", "Henrici", "Caſparis"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestEachDocumentInFileZip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "corpus")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "docs.zip")
+	bs := zipBytes(t, testDocument{"a.txt", "first"}, testDocument{"b.txt", "second"})
+	if err := ioutil.WriteFile(path, bs, 0644); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var got []string
+	err = EachDocumentInFile(path, func(name string, r io.Reader) error {
+		got = append(got, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := []string{path + "/a.txt", path + "/b.txt"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestEachDocumentErrors(t *testing.T) {
+	docs := zipBytes(t, testDocument{"a.txt", "first"}, testDocument{"b.txt", "second"})
+	t.Run("callback", func(t *testing.T) {
+		err := EachDocument("docs.zip", bytes.NewReader(docs), func(name string, r io.Reader) error {
+			if name == "docs.zip/b.txt" {
+				return errCallback
+			}
+			return nil
+		})
+		derr, ok := err.(*DocumentError)
+		if !ok {
+			t.Fatalf("expected a document error; got %v", err)
+		}
+		if derr.Name != "docs.zip/b.txt" || errors.Cause(err) != errCallback {
+			t.Fatalf("invalid error: %v", err)
+		}
+	})
+	t.Run("stop", func(t *testing.T) {
+		var n int
+		err := EachDocument("docs.zip", bytes.NewReader(docs), func(string, io.Reader) error {
+			n++
+			return ErrStop
+		})
+		if err != nil || n != 1 {
+			t.Fatalf("expected 1 call and no error; got %d and %v", n, err)
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := EachDocumentContext(ctx, "docs.zip", bytes.NewReader(docs), func(string, io.Reader) error {
+			return nil
+		})
+		if err != context.Canceled {
+			t.Fatalf("expected %v; got %v", context.Canceled, err)
+		}
+	})
+	t.Run("corrupt", func(t *testing.T) {
+		err := EachDocument("docs.zip", bytes.NewReader(docs[:20]), func(string, io.Reader) error {
+			return nil
+		})
+		if derr, ok := err.(*DocumentError); !ok || derr.Name != "docs.zip" {
+			t.Fatalf("expected a document error; got %v", err)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		err := EachDocumentInFile("testdata/missing.zip", func(string, io.Reader) error {
+			return nil
+		})
+		if _, ok := err.(*DocumentError); !ok {
+			t.Fatalf("expected a document error; got %v", err)
+		}
+	})
+}
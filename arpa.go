@@ -0,0 +1,353 @@
+package corpus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// arpaZero is the log10 probability that represents zero in ARPA files.
+const arpaZero = -99
+
+// The conventional tokens of ARPA files for unknown words and the
+// begin and end of sentences.
+const (
+	arpaUnk = "<unk>"
+	arpaBOS = "<s>"
+	arpaEOS = "</s>"
+)
+
+// ARPAWriteModel writes the language model in the ARPA format. All
+// n-grams of the model's unigrams, bigrams and trigrams are written
+// with their log10 probabilities. The backoff weights are calculated
+// such that the distribution of each context sums to one. For
+// Good-Turing, Witten-Bell and Kneser-Ney smoothing the written model
+// assigns the same probabilities as the language model. Add-k
+// smoothing does not back off, so only the probabilities of the
+// written n-grams are the same.
+//
+// UNK is written as <unk>. The language model has no sentence
+// markers, so the tokens <s> and </s> are written with a probability
+// of zero (if they are not part of the vocabulary), as is required by
+// other tools.
+func ARPAWriteModel(w io.Writer, m *LanguageModel) error {
+	ngrams := make([][][]string, m.order+1)
+	var err error
+	add := func(ngram ...string) {
+		for _, t := range ngram {
+			if err == nil && !arpaValidToken(t) {
+				err = errors.Errorf("cannot write arpa file: invalid token: %q", t)
+			}
+		}
+		ngrams[len(ngram)] = append(ngrams[len(ngram)], ngram)
+	}
+	for _, word := range m.Words() {
+		add(word)
+	}
+	for _, word := range []string{arpaBOS, arpaEOS} {
+		if !m.Known(word) {
+			add(word)
+		}
+	}
+	if m.order >= 2 {
+		m.bigrams.Each(func(a string, u *Unigrams) {
+			u.Each(func(b string, _ uint64) {
+				add(a, b)
+			})
+		})
+	}
+	if m.order >= 3 {
+		m.trigrams.Each(func(a string, b *Bigrams) {
+			b.Each(func(b string, u *Unigrams) {
+				u.Each(func(c string, _ uint64) {
+					add(a, b, c)
+				})
+			})
+		})
+	}
+	if err != nil {
+		return err
+	}
+	for n := 1; n <= m.order; n++ {
+		sortNGrams(ngrams[n])
+	}
+	bows := m.arpaBackoffWeights(ngrams)
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "\\data\\\n")
+	for n := 1; n <= m.order; n++ {
+		fmt.Fprintf(bw, "ngram %d=%d\n", n, len(ngrams[n]))
+	}
+	for n := 1; n <= m.order; n++ {
+		fmt.Fprintf(bw, "\n\\%d-grams:\n", n)
+		for _, ngram := range ngrams[n] {
+			var p float64
+			if n > 1 || m.Known(ngram[0]) {
+				p = m.Prob(ngram[n-1], ngram[:n-1]...)
+			}
+			fmt.Fprintf(bw, "%s\t%s", arpaLog(p), arpaJoin(ngram))
+			if n < m.order {
+				fmt.Fprintf(bw, "\t%s", arpaLog(bows[strings.Join(ngram, " ")]))
+			}
+			fmt.Fprintf(bw, "\n")
+		}
+	}
+	fmt.Fprintf(bw, "\n\\end\\\n")
+	if err := bw.Flush(); err != nil {
+		return errors.Wrapf(err, "cannot write arpa file")
+	}
+	return nil
+}
+
+// arpaValidToken returns true if the token can be written to an ARPA
+// file. Tokens must not be empty, must not contain white space and
+// must not be the literal <unk>, which is reserved for UNK.
+func arpaValidToken(t string) bool {
+	return t != "" && t != arpaUnk && strings.IndexFunc(t, unicode.IsSpace) == -1
+}
+
+// sortNGrams sorts the n-grams lexicographically by their tokens.
+func sortNGrams(ngrams [][]string) {
+	keys := make([]string, len(ngrams))
+	for i, ngram := range ngrams {
+		keys[i] = strings.Join(ngram, " ")
+	}
+	sort.Sort(ngramsByKey{keys, ngrams})
+}
+
+type ngramsByKey struct {
+	keys   []string
+	ngrams [][]string
+}
+
+func (s ngramsByKey) Len() int           { return len(s.keys) }
+func (s ngramsByKey) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s ngramsByKey) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.ngrams[i], s.ngrams[j] = s.ngrams[j], s.ngrams[i]
+}
+
+// arpaJoin joins the tokens of an n-gram. UNK is written as <unk>.
+func arpaJoin(ngram []string) string {
+	ts := make([]string, len(ngram))
+	for i, t := range ngram {
+		if t == UNK {
+			t = arpaUnk
+		}
+		ts[i] = t
+	}
+	return strings.Join(ts, " ")
+}
+
+// arpaBackoffWeights calculates the backoff weight of each context.
+// For a context h the weight is the probability mass left over by the
+// n-grams starting with h divided by the mass of the lower order
+// distribution for the remaining tokens.
+func (m *LanguageModel) arpaBackoffWeights(ngrams [][][]string) map[string]float64 {
+	type mass struct{ seen, lower float64 }
+	masses := make(map[string]*mass)
+	for n := 2; n <= m.order; n++ {
+		for _, ngram := range ngrams[n] {
+			h := strings.Join(ngram[:n-1], " ")
+			if masses[h] == nil {
+				masses[h] = new(mass)
+			}
+			masses[h].seen += m.Prob(ngram[n-1], ngram[:n-1]...)
+			masses[h].lower += m.Prob(ngram[n-1], ngram[1:n-1]...)
+		}
+	}
+	bows := make(map[string]float64, len(masses))
+	for n := 1; n < m.order; n++ {
+		for _, ngram := range ngrams[n] {
+			h := strings.Join(ngram, " ")
+			ms, ok := masses[h]
+			if !ok {
+				bows[h] = 1
+				continue
+			}
+			if 1-ms.lower <= 1e-12 || 1-ms.seen <= 0 {
+				bows[h] = 0
+				continue
+			}
+			bows[h] = (1 - ms.seen) / (1 - ms.lower)
+		}
+	}
+	return bows
+}
+
+func arpaLog(p float64) string {
+	if p <= 0 {
+		return strconv.Itoa(arpaZero)
+	}
+	return strconv.FormatFloat(math.Log10(p), 'g', -1, 64)
+}
+
+// ARPAModel is a backoff language model that was read from an ARPA
+// file. The token <unk> of the file is read as UNK. Tokens that are
+// not part of the unigrams are mapped to UNK. The sentence markers
+// <s> and </s> are ordinary tokens of the model; callers that want
+// to use them have to add them to the tokens and contexts.
+type ARPAModel struct {
+	order   int
+	entries map[string]arpaEntry
+}
+
+type arpaEntry struct {
+	prob, bow float64 // log10
+}
+
+// Order returns the order of the model.
+func (m *ARPAModel) Order() int {
+	return m.order
+}
+
+// Known returns true if the given token is part of the vocabulary.
+func (m *ARPAModel) Known(w string) bool {
+	_, ok := m.entries[w]
+	return ok && !strings.Contains(w, " ")
+}
+
+// Words returns the sorted vocabulary of the model.
+func (m *ARPAModel) Words() []string {
+	var words []string
+	for k := range m.entries {
+		if !strings.Contains(k, " ") {
+			words = append(words, k)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Prob returns the conditional probability P(w|ctx). Only the last
+// Order()-1 tokens of the context are used.
+func (m *ARPAModel) Prob(w string, ctx ...string) float64 {
+	if len(ctx) > m.order-1 {
+		ctx = ctx[len(ctx)-m.order+1:]
+	}
+	ngram := make([]string, 0, len(ctx)+1)
+	for _, t := range append(ctx, w) {
+		if !m.Known(t) {
+			t = UNK
+		}
+		ngram = append(ngram, t)
+	}
+	p := m.logProb(ngram)
+	if p <= arpaZero {
+		return 0
+	}
+	return math.Pow(10, p)
+}
+
+func (m *ARPAModel) logProb(ngram []string) float64 {
+	if e, ok := m.entries[strings.Join(ngram, " ")]; ok {
+		return e.prob
+	}
+	if len(ngram) == 1 {
+		return arpaZero
+	}
+	// Entries that are not found have a backoff weight of 1.
+	bow := m.entries[strings.Join(ngram[:len(ngram)-1], " ")].bow
+	if bow <= arpaZero {
+		return arpaZero
+	}
+	return bow + m.logProb(ngram[1:])
+}
+
+// ARPAReadModel reads a language model in the ARPA format.
+func ARPAReadModel(r io.Reader) (*ARPAModel, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), maxLineLength)
+	m := &ARPAModel{entries: make(map[string]arpaEntry)}
+	var counts []int
+	section := -1 // -1: before \data\, 0: \data\, n: n-grams
+	for lineno := 1; s.Scan(); lineno++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		err := m.parseLine(line, &section, &counts)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid arpa file: line %d", lineno)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrapf(err, "invalid arpa file")
+	}
+	if section < 1 {
+		return nil, errors.New("invalid arpa file: missing n-grams")
+	}
+	var n int
+	for _, c := range counts {
+		n += c
+	}
+	if n != len(m.entries) {
+		return nil, errors.Errorf("invalid arpa file: expected %d n-grams; got %d", n, len(m.entries))
+	}
+	m.order = len(counts)
+	return m, nil
+}
+
+// parseLine parses one non empty line of an ARPA file. It returns
+// io.EOF at the \end\ marker.
+func (m *ARPAModel) parseLine(line string, section *int, counts *[]int) error {
+	switch {
+	case line == "\\data\\":
+		*section = 0
+		return nil
+	case line == "\\end\\":
+		return io.EOF
+	case strings.HasPrefix(line, "\\") && strings.HasSuffix(line, "-grams:"):
+		n, err := strconv.Atoi(line[1 : len(line)-len("-grams:")])
+		if err != nil || n < 1 || n > len(*counts) {
+			return errors.Errorf("invalid section: %s", line)
+		}
+		*section = n
+		return nil
+	case *section == 0:
+		var n, c int
+		if _, err := fmt.Sscanf(line, "ngram %d=%d", &n, &c); err != nil {
+			return errors.Wrapf(err, "invalid count: %s", line)
+		}
+		if n != len(*counts)+1 {
+			return errors.Errorf("invalid count: %s", line)
+		}
+		*counts = append(*counts, c)
+		return nil
+	case *section > 0:
+		fields := strings.Fields(line)
+		n := *section
+		if len(fields) != n+1 && len(fields) != n+2 {
+			return errors.Errorf("invalid %d-gram: %s", n, line)
+		}
+		var e arpaEntry
+		var err error
+		if e.prob, err = strconv.ParseFloat(fields[0], 64); err != nil {
+			return errors.Wrapf(err, "invalid probability: %s", line)
+		}
+		if len(fields) == n+2 {
+			if e.bow, err = strconv.ParseFloat(fields[n+1], 64); err != nil {
+				return errors.Wrapf(err, "invalid backoff weight: %s", line)
+			}
+		}
+		for i := 1; i <= n; i++ {
+			if fields[i] == arpaUnk {
+				fields[i] = UNK
+			}
+		}
+		m.entries[strings.Join(fields[1:n+1], " ")] = e
+		return nil
+	default:
+		// Ignore everything before the \data\ section.
+		return nil
+	}
+}
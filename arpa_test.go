@@ -0,0 +1,208 @@
+package corpus
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestARPAWriteModel(t *testing.T) {
+	m := NewLanguageModel(WittenBell, new(Unigrams).Add("a", "b", "a"), new(Bigrams).Add("a", "b", "a"), nil)
+	buf := &bytes.Buffer{}
+	if err := ARPAWriteModel(buf, m); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	for _, want := range []string{
+		"\\data\\\nngram 1=5\nngram 2=2\n",
+		"\n\\1-grams:\n",
+		"\ta\t",
+		"\t<unk>\t",
+		"-99\t<s>\t",
+		"-99\t</s>\t",
+		"\n\\2-grams:\n",
+		"\ta b\n",
+		"\tb a\n",
+		"\n\\end\\\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expected %q in %q", want, buf.String())
+		}
+	}
+}
+
+func TestARPARoundTrip(t *testing.T) {
+	contexts := [][]string{
+		nil,
+		{"the"},
+		{"on", "the"},
+		{"the", "cat"},
+		{"unknown"},
+		{"the", "unknown"},
+		{"mat", "sat"},
+		{"dog", "sat"},
+	}
+	for _, s := range []Smoothing{AddK, GoodTuring, WittenBell, KneserNey, ModifiedKneserNey} {
+		for order := 1; order <= 3; order++ {
+			lm := newTestLanguageModel(s, order)
+			buf := &bytes.Buffer{}
+			if err := ARPAWriteModel(buf, lm); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			m, err := ARPAReadModel(buf)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if got := m.Order(); got != order {
+				t.Fatalf("expected %d; got %d", order, got)
+			}
+			for _, ctx := range contexts {
+				t.Run(fmt.Sprintf("%s %d %v", s, order, ctx), func(t *testing.T) {
+					var sum float64
+					for _, w := range m.Words() {
+						got := m.Prob(w, ctx...)
+						sum += got
+						// Add-k smoothing does not back off. The
+						// sentence markers are not part of the model.
+						if s == AddK && order > 1 || !lm.Known(w) {
+							continue
+						}
+						if want := lm.Prob(w, ctx...); math.Abs(got-want) > 1e-9 {
+							t.Fatalf("P(%s|%v): expected %g; got %g", w, ctx, want, got)
+						}
+					}
+					if math.Abs(sum-1) > 1e-9 {
+						t.Fatalf("expected 1; got %g", sum)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestARPAReadModel(t *testing.T) {
+	const arpa = `some header
+
+\data\
+ngram 1=3
+ngram 2=1
+
+\1-grams:
+-0.5	a	-0.1
+-0.5	b	-99
+-99	<unk>
+
+\2-grams:
+-0.2	a b
+
+\end\
+`
+	m, err := ARPAReadModel(strings.NewReader(arpa))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	tests := []struct {
+		w    string
+		ctx  []string
+		want float64
+	}{
+		{"a", nil, math.Pow(10, -.5)},
+		{"b", []string{"a"}, math.Pow(10, -.2)},
+		{"a", []string{"a"}, math.Pow(10, -.6)},
+		{"a", []string{"b"}, 0},
+		{"x", nil, 0},
+		{"a", []string{"x", "y"}, math.Pow(10, -.5)},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %v", tc.w, tc.ctx), func(t *testing.T) {
+			if got := m.Prob(tc.w, tc.ctx...); math.Abs(got-tc.want) > 1e-12 {
+				t.Fatalf("expected %g; got %g", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestARPAReadModelSentenceMarkers(t *testing.T) {
+	const arpa = `\data\
+ngram 1=4
+ngram 2=2
+
+\1-grams:
+-1	<unk>	0
+-99	<s>	-0.5
+-0.3	</s>	0
+-0.2	a	0
+
+\2-grams:
+-0.1	<s> a
+-0.4	a </s>
+
+\end\
+`
+	m, err := ARPAReadModel(strings.NewReader(arpa))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	tests := []struct {
+		w    string
+		ctx  []string
+		want float64
+	}{
+		{"a", []string{"<s>"}, math.Pow(10, -.1)},
+		{"</s>", []string{"a"}, math.Pow(10, -.4)},
+		{"x", []string{"<s>"}, math.Pow(10, -1.5)},
+		{UNK, nil, math.Pow(10, -1)},
+		{"<s>", nil, 0},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %v", tc.w, tc.ctx), func(t *testing.T) {
+			if got := m.Prob(tc.w, tc.ctx...); math.Abs(got-tc.want) > 1e-12 {
+				t.Fatalf("expected %g; got %g", tc.want, got)
+			}
+		})
+	}
+	if !m.Known(UNK) || m.Known("<unk>") {
+		t.Fatalf("expected <unk> to be read as %s", UNK)
+	}
+}
+
+func TestARPAReadModelErrors(t *testing.T) {
+	tests := []struct{ test string }{
+		{""},
+		{"\\data\\\nngram 1=1\n"},
+		{"\\data\\\nngram 2=1\n"},
+		{"\\data\\\nngram x\n"},
+		{"\\data\\\nngram 1=2\n\\1-grams:\n-1\ta\n\\end\\\n"},
+		{"\\data\\\nngram 1=1\n\\1-grams:\nx\ta\n\\end\\\n"},
+		{"\\data\\\nngram 1=1\n\\1-grams:\n-1\ta\tx\n\\end\\\n"},
+		{"\\data\\\nngram 1=1\n\\1-grams:\n-1\ta b c\n\\end\\\n"},
+		{"\\data\\\nngram 1=1\n\\2-grams:\n-1\ta b\n\\end\\\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.test, func(t *testing.T) {
+			if _, err := ARPAReadModel(strings.NewReader(tc.test)); err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+		})
+	}
+}
+
+func TestARPAWriteModelInvalidToken(t *testing.T) {
+	for _, token := range []string{"a b", "<unk>"} {
+		t.Run(token, func(t *testing.T) {
+			m := NewLanguageModel(AddK, new(Unigrams).Add(token), nil, nil)
+			buf := &bytes.Buffer{}
+			if err := ARPAWriteModel(buf, m); err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+			if buf.Len() != 0 {
+				t.Fatalf("expected no output; got %q", buf.String())
+			}
+		})
+	}
+	m := NewLanguageModel(AddK, new(Unigrams).Add("a"), nil, nil)
+	if err := ARPAWriteModel(errWriter{}, m); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
@@ -0,0 +1,222 @@
+package corpus
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// EachCharNGram iterates over all character n-grams of the given
+// order in the given string. It calls the supplied callback function
+// for each such n-gram. N-grams are built from runes, not bytes.
+func EachCharNGram(str string, n int, f func(string)) {
+	if n < 1 {
+		return
+	}
+	pos := make([]int, 0, len(str)+1)
+	for i := range str {
+		pos = append(pos, i)
+	}
+	pos = append(pos, len(str))
+	for i := 0; i+n < len(pos); i++ {
+		f(str[pos[i]:pos[i+n]])
+	}
+}
+
+// EachPaddedCharNGram iterates over all character n-grams of the
+// given order in the given string. The string is padded with n-1
+// padding symbols on both sides, so that the n-grams at the
+// boundaries of the string are reported as well. Nothing is reported
+// for empty strings.
+func EachPaddedCharNGram(str string, n int, pad rune, f func(string)) {
+	if str == "" || n < 1 {
+		return
+	}
+	p := strings.Repeat(string(pad), n-1)
+	EachCharNGram(p+str+p, n, f)
+}
+
+// CharNGrams is a map of character n-grams of the orders 1 to n.
+// The counts of the different orders are kept separately. If a
+// padding symbol is set, the n-grams at the boundaries of the
+// strings are counted with padding (see EachPaddedCharNGram).
+type CharNGrams struct {
+	order  int
+	pad    rune
+	ngrams map[int]*charCounts // the counts of each order
+}
+
+// charCounts holds the counts of the character n-grams of one order.
+type charCounts struct {
+	m     map[string]uint64
+	total uint64
+}
+
+func (c *charCounts) get(str string) uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.m[str]
+}
+
+func (c *charCounts) each(f func(string, uint64)) {
+	if c == nil {
+		return
+	}
+	for k, v := range c.m {
+		f(k, v)
+	}
+}
+
+// NewCharNGrams creates a new character n-gram map for the orders 1
+// to order. If pad is not 0, the strings are padded with it.
+func NewCharNGrams(order int, pad rune) *CharNGrams {
+	return &CharNGrams{order: order, pad: pad}
+}
+
+// Order returns the maximal order of the n-grams in the map.
+func (m *CharNGrams) Order() int {
+	if m == nil {
+		return 0
+	}
+	return m.order
+}
+
+// Pad returns the padding symbol of the map or 0 if the strings are
+// not padded.
+func (m *CharNGrams) Pad() rune {
+	if m == nil {
+		return 0
+	}
+	return m.pad
+}
+
+// Add adds all character n-grams of the orders 1 to n of the
+// supplied string into the map.
+func (m *CharNGrams) Add(str string) *CharNGrams {
+	for n := 1; n <= m.order; n++ {
+		f := func(str string) { m.add(n, str, 1) }
+		if m.pad == 0 {
+			EachCharNGram(str, n, f)
+		} else {
+			EachPaddedCharNGram(str, n, m.pad, f)
+		}
+	}
+	return m
+}
+
+func (m *CharNGrams) add(n int, str string, c uint64) {
+	if m.ngrams == nil {
+		m.ngrams = make(map[int]*charCounts)
+	}
+	ngrams, ok := m.ngrams[n]
+	if !ok {
+		ngrams = &charCounts{m: make(map[string]uint64)}
+		m.ngrams[n] = ngrams
+	}
+	ngrams.m[str] += c
+	ngrams.total += c
+}
+
+// Append appends the n-grams of another map to this.
+func (m *CharNGrams) Append(o *CharNGrams) *CharNGrams {
+	if o == nil {
+		return m
+	}
+	for n, ngrams := range o.ngrams {
+		ngrams.each(func(str string, c uint64) {
+			m.add(n, str, c)
+		})
+	}
+	return m
+}
+
+// Get returns the count of the supplied n-gram. The order of the
+// n-gram is the number of runes in the string.
+func (m *CharNGrams) Get(str string) uint64 {
+	if m == nil {
+		return 0
+	}
+	return m.ngrams[utf8.RuneCountInString(str)].get(str)
+}
+
+// Total returns the total number of n-grams of the given order.
+func (m *CharNGrams) Total(n int) uint64 {
+	if m == nil || m.ngrams[n] == nil {
+		return 0
+	}
+	return m.ngrams[n].total
+}
+
+// Len returns the number of different n-grams of the given order.
+func (m *CharNGrams) Len(n int) uint64 {
+	if m == nil || m.ngrams[n] == nil {
+		return 0
+	}
+	return uint64(len(m.ngrams[n].m))
+}
+
+// Each iterates over all character n-grams of the given order.
+func (m *CharNGrams) Each(n int, f func(string, uint64)) {
+	if m == nil {
+		return
+	}
+	m.ngrams[n].each(f)
+}
+
+type jsonCharNGrams struct {
+	Order  int
+	Pad    rune
+	NGrams map[int]jsonMap
+}
+
+// MarshalJSON implements JSON marshaling.
+func (m *CharNGrams) MarshalJSON() ([]byte, error) {
+	return m.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling.
+func (m *CharNGrams) UnmarshalJSON(bs []byte) error {
+	return m.unmarshal(bs, json.Unmarshal)
+}
+
+// GobEncode implement gob marhsaling.
+func (m *CharNGrams) GobEncode() ([]byte, error) {
+	return m.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling.
+func (m *CharNGrams) GobDecode(bs []byte) error {
+	return m.unmarshal(bs, unmarshalGob)
+}
+
+func (m *CharNGrams) marshal(f marshalFunc) ([]byte, error) {
+	tmp := jsonCharNGrams{
+		Order:  m.Order(),
+		Pad:    m.Pad(),
+		NGrams: make(map[int]jsonMap, len(m.ngrams)),
+	}
+	for n, ngrams := range m.ngrams {
+		tmp.NGrams[n] = jsonMap{
+			Total:  ngrams.total,
+			Len:    uint64(len(ngrams.m)),
+			NGrams: ngrams.m,
+		}
+	}
+	return f(tmp)
+}
+
+func (m *CharNGrams) unmarshal(bs []byte, f unmarshalFunc) error {
+	var tmp jsonCharNGrams
+	if err := f(bs, &tmp); err != nil {
+		return err
+	}
+	*m = CharNGrams{order: tmp.Order, pad: tmp.Pad}
+	for n, ngrams := range tmp.NGrams {
+		if m.ngrams == nil {
+			m.ngrams = make(map[int]*charCounts)
+		}
+		m.ngrams[n] = &charCounts{total: ngrams.Total, m: ngrams.NGrams}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestEachCharNGram(t *testing.T) {
+	tests := []struct {
+		test string
+		n    int
+		want []string
+	}{
+		{"abc", 0, nil},
+		{"abc", 1, []string{"a", "b", "c"}},
+		{"Bäume", 2, []string{"Bä", "äu", "um", "me"}},
+		{"Größe", 3, []string{"Grö", "röß", "öße"}},
+		{"Größe", 5, []string{"Größe"}},
+		{"Größe", 6, nil},
+		{"", 1, nil},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %d", tc.test, tc.n), func(t *testing.T) {
+			var got []string
+			EachCharNGram(tc.test, tc.n, func(str string) {
+				got = append(got, str)
+			})
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEachPaddedCharNGram(t *testing.T) {
+	tests := []struct {
+		test string
+		n    int
+		want []string
+	}{
+		{"ab", 1, []string{"a", "b"}},
+		{"ab", 2, []string{"#a", "ab", "b#"}},
+		{"ab", 3, []string{"##a", "#ab", "ab#", "b##"}},
+		{"ä", 2, []string{"#ä", "ä#"}},
+		{"", 2, nil},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %d", tc.test, tc.n), func(t *testing.T) {
+			var got []string
+			EachPaddedCharNGram(tc.test, tc.n, '#', func(str string) {
+				got = append(got, str)
+			})
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCharNGrams(t *testing.T) {
+	m := NewCharNGrams(3, '#').Add("aba").Add("ä")
+	tests := []struct {
+		ngram string
+		count uint64
+	}{
+		{"a", 2},
+		{"ä", 1},
+		{"#a", 1},
+		{"ab", 1},
+		{"a#", 1},
+		{"#ä", 1},
+		{"##a", 1},
+		{"aba", 1},
+		{"ba#", 1},
+		{"x", 0},
+		{"abab", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ngram, func(t *testing.T) {
+			if got := m.Get(tc.ngram); got != tc.count {
+				t.Fatalf("expected %d; got %d", tc.count, got)
+			}
+		})
+	}
+	sizes := []struct {
+		n          int
+		total, len uint64
+	}{
+		{1, 4, 3},
+		{2, 6, 6},
+		{3, 8, 8},
+		{4, 0, 0},
+	}
+	for _, tc := range sizes {
+		t.Run(fmt.Sprintf("order %d", tc.n), func(t *testing.T) {
+			if got := m.Total(tc.n); got != tc.total {
+				t.Fatalf("expected %d; got %d", tc.total, got)
+			}
+			if got := m.Len(tc.n); got != tc.len {
+				t.Fatalf("expected %d; got %d", tc.len, got)
+			}
+		})
+	}
+}
+
+func TestCharNGramsCharTrigrams(t *testing.T) {
+	want := make(map[string]uint64)
+	new(CharTrigrams).Add("abababa").Add("Größe").Each(func(str string, n uint64) {
+		want[str] = n
+	})
+	got := make(map[string]uint64)
+	NewCharNGrams(3, 0).Add("abababa").Add("Größe").Each(3, func(str string, n uint64) {
+		got[str] = n
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestCharNGramsAppend(t *testing.T) {
+	m := NewCharNGrams(2, 0).Add("ab").Append(NewCharNGrams(2, 0).Add("abc")).Append(nil)
+	if got := m.Get("ab"); got != 2 {
+		t.Fatalf("expected 2; got %d", got)
+	}
+	if got := m.Total(1); got != 5 {
+		t.Fatalf("expected 5; got %d", got)
+	}
+}
+
+func TestCharNGramsMarshal(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(interface{}) ([]byte, error)
+		unmarshal func([]byte, interface{}) error
+	}{
+		{"json", json.Marshal, json.Unmarshal},
+		{"gob", marshalGob, unmarshalGob},
+	}
+	for _, c := range codecs {
+		for _, want := range []*CharNGrams{
+			NewCharNGrams(2, 0),
+			NewCharNGrams(3, '$').Add("Größe"),
+		} {
+			t.Run(fmt.Sprintf("%s %d", c.name, want.Total(1)), func(t *testing.T) {
+				bs, err := c.marshal(want)
+				if err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+				got := new(CharNGrams)
+				if err := c.unmarshal(bs, got); err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("expected %v; got %v", want, got)
+				}
+			})
+		}
+	}
+}
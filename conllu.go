@@ -0,0 +1,222 @@
+package corpus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// conlluCorrectForm is the name of the MISC attribute that
+// holds the orthographic correction of a token.
+const conlluCorrectForm = "CorrectForm="
+
+// CoNLLUWriteSentences writes the sentences in CoNLL-U format.
+// The FORM, LEMMA, UPOS, XPOS (POS), FEATS and MISC columns are
+// written. The correction of a token is written as CorrectForm
+// attribute in the MISC column. Tokens are numbered from 1 in each
+// sentence. The HEAD, DEPREL and DEPS columns are left empty. Values
+// that contain tabs or newlines cannot be written and result in an
+// error.
+func CoNLLUWriteSentences(w io.Writer, ss []Sentence) error {
+	bw := bufio.NewWriter(w)
+	for _, s := range ss {
+		if s.ID != "" {
+			if err := checkCoNLLU("sent_id", s.ID); err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, "# sent_id = %s\n", s.ID)
+		}
+		for _, c := range s.Comments {
+			if err := checkCoNLLU("comment", c); err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, "# %s\n", c)
+		}
+		for i, t := range s.Tokens {
+			misc := t.Misc
+			if t.Correction != "" {
+				misc = joinCoNLLUMisc(misc, conlluCorrectForm+t.Correction)
+			}
+			if err := checkCoNLLU("FORM", string(t.Token), "LEMMA", t.Lemma,
+				"UPOS", t.UPOS, "XPOS", t.POS, "FEATS", t.Features,
+				"MISC", misc); err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, "%d\t%s\t%s\t%s\t%s\t%s\t_\t_\t_\t%s\n",
+				i+1, conlluField(string(t.Token)), conlluField(t.Lemma),
+				conlluField(t.UPOS), conlluField(t.POS),
+				conlluField(t.Features), conlluField(misc))
+		}
+		bw.WriteString("\n")
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrapf(err, "cannot write conllu file")
+	}
+	return nil
+}
+
+// CoNLLU reads tokens from CoNLL-U files. The tokens of
+// the FORM column are split in the same way as DTAReadTokens
+// splits them.
+type CoNLLU struct {
+	r io.Reader
+}
+
+// NewCoNLLU creates a new CoNLL-U reader that reads from the given reader.
+func NewCoNLLU(r io.Reader) *CoNLLU {
+	return &CoNLLU{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (c *CoNLLU) Tokens(f func(Token)) error {
+	return c.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface.
+func (c *CoNLLU) TokensContext(ctx context.Context, f TokenFunc) error {
+	return CoNLLUReadSentencesContext(ctx, c.r, func(s Sentence) error {
+		for _, t := range s.Tokens {
+			if err := tokenize(string(t.Token), f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CoNLLUReadSentences reads all sentences from a CoNLL-U file.
+// Multiword tokens and empty nodes are skipped. The sentence ID is
+// read from the sent_id comment; sentences without sent_id comment
+// are numbered s1, s2, ... All other comments are kept without
+// their leading `#`. The tokens get the sentence ID and their ID
+// column as IDs.
+func CoNLLUReadSentences(r io.Reader, f func(Sentence)) error {
+	return CoNLLUReadSentencesContext(context.Background(), r,
+		func(s Sentence) error {
+			f(s)
+			return nil
+		})
+}
+
+// CoNLLUReadSentencesContext reads all sentences from a CoNLL-U file.
+// It handles cancellation and errors in the same way as
+// DTAReadTokensContext.
+func CoNLLUReadSentencesContext(ctx context.Context, r io.Reader, f func(Sentence) error) error {
+	s := bufio.NewScanner(contextReader{ctx: ctx, r: r})
+	s.Buffer(nil, maxLineLength)
+	var n, lineno int
+	var sentence Sentence
+	flush := func() error {
+		if len(sentence.Tokens) == 0 && len(sentence.Comments) == 0 && sentence.ID == "" {
+			return nil
+		}
+		n++
+		if sentence.ID == "" {
+			sentence.ID = fmt.Sprintf("s%d", n)
+		}
+		for i := range sentence.Tokens {
+			sentence.Tokens[i].Sentence = sentence.ID
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := f(sentence)
+		sentence = Sentence{}
+		return err
+	}
+	for s.Scan() {
+		lineno++
+		line := s.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if err := flush(); err != nil {
+				return stopped(err)
+			}
+		case strings.HasPrefix(line, "#"):
+			c := strings.TrimSpace(line[1:])
+			if i := strings.Index(c, "="); i != -1 && strings.TrimSpace(c[:i]) == "sent_id" {
+				sentence.ID = strings.TrimSpace(c[i+1:])
+			} else {
+				sentence.Comments = append(sentence.Comments, c)
+			}
+		default:
+			t, ok, err := parseCoNLLUToken(line)
+			if err != nil {
+				return errors.Wrapf(err, "invalid conllu file: line %d", lineno)
+			}
+			if ok {
+				sentence.Tokens = append(sentence.Tokens, t)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return wrapContext(ctx, err, "invalid conllu file")
+	}
+	return stopped(flush())
+}
+
+func parseCoNLLUToken(line string) (AnnotatedToken, bool, error) {
+	cols := strings.Split(line, "\t")
+	if len(cols) != 10 {
+		return AnnotatedToken{}, false, errors.Errorf("expected 10 columns; got %d", len(cols))
+	}
+	if strings.ContainsAny(cols[0], "-.") {
+		return AnnotatedToken{}, false, nil
+	}
+	t := AnnotatedToken{
+		ID:       cols[0],
+		Token:    Token(cols[1]),
+		Lemma:    conlluValue(cols[2]),
+		UPOS:     conlluValue(cols[3]),
+		POS:      conlluValue(cols[4]),
+		Features: conlluValue(cols[5]),
+	}
+	var misc []string
+	for _, m := range strings.Split(conlluValue(cols[9]), "|") {
+		switch {
+		case m == "":
+		case strings.HasPrefix(m, conlluCorrectForm):
+			t.Correction = m[len(conlluCorrectForm):]
+		default:
+			misc = append(misc, m)
+		}
+	}
+	t.Misc = strings.Join(misc, "|")
+	return t, true, nil
+}
+
+// checkCoNLLU checks pairs of column names and values. It returns an
+// error if a value contains a tab or a newline.
+func checkCoNLLU(kvs ...string) error {
+	for i := 1; i < len(kvs); i += 2 {
+		if strings.ContainsAny(kvs[i], "\t\r\n") {
+			return errors.Errorf("cannot write conllu file: invalid %s: %q", kvs[i-1], kvs[i])
+		}
+	}
+	return nil
+}
+
+func joinCoNLLUMisc(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "|" + b
+}
+
+func conlluField(str string) string {
+	if str == "" {
+		return "_"
+	}
+	return str
+}
+
+func conlluValue(str string) string {
+	if str == "_" {
+		return ""
+	}
+	return str
+}
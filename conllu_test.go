@@ -0,0 +1,150 @@
+package corpus
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCoNLLU = `# sent_id = s1
+# text = D. Henrici Caſparis
+1	D.	D.	PROPN	NE	_	_	_	_	_
+2-3	Henrici	_	_	_	_	_	_	_	_
+2	Henrici	Henrici	PROPN	NE	Case=Gen	_	_	_	SpaceAfter=No
+3	Caſparis	Casparis	PROPN	NE	_	_	_	_	CorrectForm=Casparis|SpaceAfter=No
+3.1	x	_	_	_	_	_	_	_	_
+
+# sent_identifier = x
+1	Abelii	_	_	_	_	_	_	_	_
+`
+
+func TestCoNLLUReadSentences(t *testing.T) {
+	want := []Sentence{
+		{ID: "s1", Comments: []string{"text = D. Henrici Caſparis"}, Tokens: []AnnotatedToken{
+			{ID: "1", Sentence: "s1", Token: "D.", Lemma: "D.", UPOS: "PROPN", POS: "NE"},
+			{ID: "2", Sentence: "s1", Token: "Henrici", Lemma: "Henrici", UPOS: "PROPN",
+				POS: "NE", Features: "Case=Gen", Misc: "SpaceAfter=No"},
+			{ID: "3", Sentence: "s1", Token: "Caſparis", Lemma: "Casparis", UPOS: "PROPN",
+				POS: "NE", Correction: "Casparis", Misc: "SpaceAfter=No"},
+		}},
+		{ID: "s2", Comments: []string{"sent_identifier = x"}, Tokens: []AnnotatedToken{
+			{ID: "1", Sentence: "s2", Token: "Abelii"},
+		}},
+	}
+	var got []Sentence
+	err := CoNLLUReadSentences(strings.NewReader(testCoNLLU), func(s Sentence) {
+		got = append(got, s)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestCoNLLUReadSentencesError(t *testing.T) {
+	err := CoNLLUReadSentences(strings.NewReader("1\tD.\t_\n"), func(Sentence) {})
+	if err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
+
+func TestCoNLLUWriteSentences(t *testing.T) {
+	ss := []Sentence{{ID: "s1", Comments: []string{"text = a b"}, Tokens: []AnnotatedToken{
+		{Token: "a", Lemma: "A", POS: "NN", Correction: "b", Misc: "SpaceAfter=No"},
+		{Token: "b"},
+	}}}
+	want := "# sent_id = s1\n# text = a b\n" +
+		"1\ta\tA\t_\tNN\t_\t_\t_\t_\tSpaceAfter=No|CorrectForm=b\n" +
+		"2\tb\t_\t_\t_\t_\t_\t_\t_\t_\n\n"
+	buf := &bytes.Buffer{}
+	if err := CoNLLUWriteSentences(buf, ss); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("expected %q; got %q", want, got)
+	}
+	if err := CoNLLUWriteSentences(errWriter{}, ss); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
+
+func TestCoNLLUWriteSentencesInvalid(t *testing.T) {
+	tests := []Sentence{
+		{ID: "s\n1"},
+		{Comments: []string{"a\nb"}},
+		{Tokens: []AnnotatedToken{{Token: "a\tb"}}},
+		{Tokens: []AnnotatedToken{{Token: "a", Lemma: "a\n"}}},
+		{Tokens: []AnnotatedToken{{Token: "a", Misc: "x\ty"}}},
+		{Tokens: []AnnotatedToken{{Token: "a", Correction: "b\n"}}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%q", tc), func(t *testing.T) {
+			if err := CoNLLUWriteSentences(ioutil.Discard, []Sentence{tc}); err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+		})
+	}
+}
+
+func TestCoNLLUDTARoundTrip(t *testing.T) {
+	var want []AnnotatedToken
+	err := DTAReadAnnotatedTokensAndClose(openDTATestFile(t), func(t AnnotatedToken) {
+		want = append(want, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	conllu := &bytes.Buffer{}
+	if err := CoNLLUWriteSentences(conllu, SentencesOf(want)); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var ts []AnnotatedToken
+	err = CoNLLUReadSentences(conllu, func(s Sentence) {
+		ts = append(ts, s.Tokens...)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	tcf := &bytes.Buffer{}
+	if err := DTAWriteAnnotatedTokens(tcf, "de", ts); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var got []AnnotatedToken
+	err = DTAReadAnnotatedTokens(tcf, func(t AnnotatedToken) {
+		got = append(got, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	// The tokens without sentence are read as second sentence.
+	for i := range want {
+		if want[i].Sentence == "" {
+			want[i].Sentence = "s2"
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestSentencesOf(t *testing.T) {
+	ts := []AnnotatedToken{
+		{Token: "a", Sentence: "s1"},
+		{Token: "b", Sentence: "s1"},
+		{Token: "c"},
+		{Token: "d", Sentence: "s2"},
+	}
+	want := []Sentence{
+		{ID: "s1", Tokens: ts[0:2]},
+		{ID: "", Tokens: ts[2:3]},
+		{ID: "s2", Tokens: ts[3:4]},
+	}
+	if got := SentencesOf(ts); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package corpus
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// contextReader is a reader that fails if its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// wrapContext wraps the given error with the given message.
+// If the context is done, the context's error is returned instead.
+func wrapContext(ctx context.Context, err error, msg string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return errors.Wrap(err, msg)
+}
+
+// stopped returns nil if the given error is ErrStop.
+func stopped(err error) error {
+	if err == ErrStop {
+		return nil
+	}
+	return err
+}
+
+// withContext returns a callback that fails if the context is done.
+func withContext(ctx context.Context, f TokenFunc) TokenFunc {
+	return func(t Token) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return f(t)
+	}
+}
@@ -0,0 +1,135 @@
+package corpus
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+var errCallback = errors.New("callback")
+
+func contextReaders() map[string]func(context.Context, io.Reader, TokenFunc) error {
+	return map[string]func(context.Context, io.Reader, TokenFunc) error{
+		"tokens": DTAReadTokensContext,
+		"annotated": func(ctx context.Context, r io.Reader, f TokenFunc) error {
+			return DTAReadAnnotatedTokensContext(ctx, r, func(t AnnotatedToken) error {
+				return f(t.Token)
+			})
+		},
+		"sentences": func(ctx context.Context, r io.Reader, f TokenFunc) error {
+			return DTAReadSentencesContext(ctx, r, func(s []Token) error {
+				return f(s[0])
+			})
+		},
+		"tei": func(ctx context.Context, r io.Reader, f TokenFunc) error {
+			return NewTEI(r).TokensContext(ctx, f)
+		},
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	for name, read := range contextReaders() {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			r := openDTATestFile(t)
+			defer r.Close()
+			err := read(ctx, r, func(Token) error { return nil })
+			if err != context.Canceled {
+				t.Fatalf("expected %v; got %v", context.Canceled, err)
+			}
+		})
+	}
+}
+
+func TestContextCanceledWhileReading(t *testing.T) {
+	for name, read := range contextReaders() {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			r := openDTATestFile(t)
+			defer r.Close()
+			var n int
+			err := read(ctx, r, func(Token) error {
+				n++
+				cancel()
+				return nil
+			})
+			if err != context.Canceled {
+				t.Fatalf("expected %v; got %v", context.Canceled, err)
+			}
+			if n != 1 {
+				t.Fatalf("expected 1 call; got %d", n)
+			}
+		})
+	}
+}
+
+func TestContextCallbackError(t *testing.T) {
+	for name, read := range contextReaders() {
+		t.Run(name, func(t *testing.T) {
+			r := openDTATestFile(t)
+			defer r.Close()
+			var n int
+			err := read(context.Background(), r, func(Token) error {
+				n++
+				return errCallback
+			})
+			if err != errCallback {
+				t.Fatalf("expected %v; got %v", errCallback, err)
+			}
+			if n != 1 {
+				t.Fatalf("expected 1 call; got %d", n)
+			}
+		})
+	}
+}
+
+func TestContextSyntaxError(t *testing.T) {
+	for name, read := range contextReaders() {
+		t.Run(name, func(t *testing.T) {
+			r := strings.NewReader(`<TextCorpus><tokens><token>a</tokens>`)
+			err := read(context.Background(), r, func(Token) error { return nil })
+			if _, ok := errors.Cause(err).(*xml.SyntaxError); !ok {
+				t.Fatalf("expected a syntax error; got %v", err)
+			}
+		})
+	}
+}
+
+func TestDTAReadMetadataContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := openDTATestFile(t)
+	defer r.Close()
+	if _, err := DTAReadMetadataContext(ctx, r); err != context.Canceled {
+		t.Fatalf("expected %v; got %v", context.Canceled, err)
+	}
+}
+
+func TestContextStop(t *testing.T) {
+	for name, read := range contextReaders() {
+		t.Run(name, func(t *testing.T) {
+			r := openDTATestFile(t)
+			defer r.Close()
+			var n int
+			err := read(context.Background(), r, func(Token) error {
+				n++
+				if n == 2 {
+					return ErrStop
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if n != 2 {
+				t.Fatalf("expected 2 calls; got %d", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package corpus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sniffLength is the number of bytes used to detect the format of a document.
+const sniffLength = 4096
+
+// Errors collects the errors of multiple documents.
+type Errors []error
+
+func (es Errors) Error() string {
+	strs := make([]string, len(es))
+	for i, e := range es {
+		strs[i] = e.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(es), strings.Join(strs, "; "))
+}
+
+// Corpus represents a collection of corpus documents. Paths can be
+// files, directories or glob patterns. Directories are walked
+// recursively. The files can be compressed or archived (see
+// EachDocument). The format of each document is detected using
+// DetectFormat.
+//
+// By default reading stops at the first broken document. If
+// CollectErrors is set, broken documents are skipped and their
+// errors are returned as Errors after all documents have been read.
+type Corpus struct {
+	Paths         []string
+	CollectErrors bool
+}
+
+// NewCorpus creates a new corpus with the given paths.
+func NewCorpus(paths ...string) *Corpus {
+	return &Corpus{Paths: paths}
+}
+
+// CorpusFunc is the type of the callback function that is called for
+// each document of a corpus. The reader is only valid during the call.
+type CorpusFunc func(name string, format Format, r io.Reader) error
+
+// Documents calls the callback function for each document of the
+// corpus in lexical order. Errors returned by the callback function
+// are handled as errors of the document. If the callback function
+// returns ErrStop, reading stops and nil is returned. If the context
+// is canceled, ctx.Err() is returned.
+func (c *Corpus) Documents(ctx context.Context, f CorpusFunc) error {
+	var errs Errors
+	err := c.documents(ctx, f, func(err error) {
+		errs = append(errs, err)
+	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// documents calls the callback function for each document of the
+// corpus. If CollectErrors is set, the errors of broken documents are
+// passed to collect.
+func (c *Corpus) documents(ctx context.Context, f CorpusFunc, collect func(error)) error {
+	var stop bool
+	// handle returns nil if the error should be collected.
+	handle := func(err error) error {
+		if err == nil || !c.CollectErrors || ctx.Err() != nil {
+			return err
+		}
+		collect(err)
+		return nil
+	}
+	for _, path := range c.Paths {
+		paths, err := c.files(path, handle)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			err := EachDocumentInFileContext(ctx, path, func(name string, r io.Reader) error {
+				err := c.document(name, r, f)
+				if err == ErrStop {
+					stop = true
+					return err
+				}
+				return handle(documentError(ctx, name, err))
+			})
+			if err := handle(err); err != nil {
+				return err
+			}
+			if stop {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Tokens calls the callback function for each token of each document
+// of the corpus. Errors returned by the callback function stop the
+// reading and are returned as they are.
+func (c *Corpus) Tokens(ctx context.Context, f func(name string, t Token) error) error {
+	var ferr error
+	err := c.Documents(ctx, func(name string, format Format, r io.Reader) error {
+		err := format.NewTokener(r).TokensContext(ctx, func(t Token) error {
+			if ferr = f(name, t); ferr != nil {
+				return ErrStop
+			}
+			return nil
+		})
+		if ferr != nil {
+			return ErrStop
+		}
+		return err
+	})
+	if ferr != nil {
+		return stopped(ferr)
+	}
+	return err
+}
+
+func (c *Corpus) document(name string, r io.Reader, f CorpusFunc) error {
+	br := bufio.NewReaderSize(r, sniffLength)
+	head, err := br.Peek(sniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+	format := DetectFormat(name, head)
+	if format == UnknownFormat {
+		return errors.New("unknown format")
+	}
+	return f(name, format, br)
+}
+
+// files returns the files for the given path. Errors are passed to
+// handle; if handle returns nil, the error is skipped and the
+// remaining files are collected.
+func (c *Corpus) files(path string, handle func(error) error) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		paths, err := filepath.Glob(path)
+		if err != nil {
+			return nil, handle(errors.Wrapf(err, "invalid pattern: %s", path))
+		}
+		var files []string
+		for _, path := range paths {
+			fs, err := c.files(path, handle)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, fs...)
+		}
+		return files, nil
+	}
+	var files []string
+	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return handle(&DocumentError{Name: path, Err: err})
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
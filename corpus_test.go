@@ -0,0 +1,202 @@
+package corpus
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestCorpusDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "corpus")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	dta, err := ioutil.ReadFile("testdata/dta.xml")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	page, err := ioutil.ReadFile("testdata/page.xml")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{"a/dta.xml", dta},
+		{"a/text.txt", []byte("a b\nc")},
+		{"b/broken.xml", []byte("<TEI><text>")},
+		{"b/page.xml.gz", gzipBytes(t, page)},
+		{"c/unknown.bin", []byte{0, 1, 2}},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if err := ioutil.WriteFile(path, f.content, 0644); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	return dir
+}
+
+func countCorpusTokens(c *Corpus) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := c.Tokens(context.Background(), func(name string, t Token) error {
+		counts[name]++
+		return nil
+	})
+	return counts, err
+}
+
+func TestCorpusCollectErrors(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	c := NewCorpus(dir)
+	c.CollectErrors = true
+	got, err := countCorpusTokens(c)
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("expected 2 errors; got %v", err)
+	}
+	for i, name := range []string{"b/broken.xml", "c/unknown.bin"} {
+		derr, ok := errs[i].(*DocumentError)
+		if !ok || derr.Name != filepath.Join(dir, name) {
+			t.Fatalf("invalid error: %v", errs[i])
+		}
+	}
+	want := map[string]int{
+		filepath.Join(dir, "a/dta.xml"):  22,
+		filepath.Join(dir, "a/text.txt"): 3,
+		filepath.Join(dir, "b/page.xml"): 6,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestCorpusCollectWalkErrors(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("cannot test unreadable directories as root")
+	}
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	if err := os.Chmod(filepath.Join(dir, "b"), 0); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer os.Chmod(filepath.Join(dir, "b"), 0755)
+	c := NewCorpus(dir)
+	c.CollectErrors = true
+	got, err := countCorpusTokens(c)
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("expected 2 errors; got %v", err)
+	}
+	if derr, ok := errs[0].(*DocumentError); !ok || derr.Name != filepath.Join(dir, "b") {
+		t.Fatalf("invalid error: %v", errs[0])
+	}
+	want := map[string]int{
+		filepath.Join(dir, "a/dta.xml"):  22,
+		filepath.Join(dir, "a/text.txt"): 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestCorpusStopAtFirstError(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	got, err := countCorpusTokens(NewCorpus(dir))
+	derr, ok := err.(*DocumentError)
+	if !ok || derr.Name != filepath.Join(dir, "b/broken.xml") {
+		t.Fatalf("invalid error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 documents; got %v", got)
+	}
+}
+
+func TestCorpusGlob(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	got, err := countCorpusTokens(NewCorpus(filepath.Join(dir, "*", "*.txt"), filepath.Join(dir, "b/*.gz")))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want := map[string]int{
+		filepath.Join(dir, "a/text.txt"): 3,
+		filepath.Join(dir, "b/page.xml"): 6,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestCorpusDocuments(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	var got []Format
+	err := NewCorpus(filepath.Join(dir, "a"), filepath.Join(dir, "b/page.xml.gz")).Documents(
+		context.Background(), func(name string, f Format, r io.Reader) error {
+			got = append(got, f)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := []Format{DTAFormat, TextFormat, PAGEFormat}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestCorpusCallbackErrors(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	c := NewCorpus(dir)
+	c.CollectErrors = true
+	tests := []struct {
+		ret, want error
+	}{
+		{ErrStop, nil},
+		{errCallback, errCallback},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ret.Error(), func(t *testing.T) {
+			var n int
+			err := c.Tokens(context.Background(), func(string, Token) error {
+				n++
+				return tc.ret
+			})
+			if err != tc.want {
+				t.Fatalf("expected %v; got %v", tc.want, err)
+			}
+			if n != 1 {
+				t.Fatalf("expected 1 call; got %d", n)
+			}
+		})
+	}
+}
+
+func TestCorpusErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := NewCorpus("testdata").Tokens(ctx, func(string, Token) error { return nil })
+	if err != context.Canceled {
+		t.Fatalf("expected %v; got %v", context.Canceled, err)
+	}
+	err = NewCorpus("testdata/missing").Tokens(context.Background(), func(string, Token) error {
+		return nil
+	})
+	if _, ok := err.(*DocumentError); !ok {
+		t.Fatalf("expected a document error; got %v", err)
+	}
+	if _, err := countCorpusTokens(NewCorpus("[")); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
@@ -0,0 +1,162 @@
+package corpus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+)
+
+// Counts holds the n-gram counts of a corpus.
+type Counts struct {
+	Unigrams     Unigrams
+	Bigrams      Bigrams
+	Trigrams     Trigrams
+	CharTrigrams CharTrigrams
+}
+
+// Add adds the n-grams of a sequence of tokens to the counts.
+// The character 3-grams are counted for each token.
+func (c *Counts) Add(ts ...string) *Counts {
+	c.Unigrams.Add(ts...)
+	c.Bigrams.Add(ts...)
+	c.Trigrams.Add(ts...)
+	for _, t := range ts {
+		c.CharTrigrams.Add(t)
+	}
+	return c
+}
+
+// Append appends all counts of another Counts to this.
+func (c *Counts) Append(o *Counts) *Counts {
+	if o == nil {
+		return c
+	}
+	c.Unigrams.AddUnigrams(&o.Unigrams)
+	c.Bigrams.Append(&o.Bigrams)
+	c.Trigrams.Append(&o.Trigrams)
+	c.CharTrigrams.Append(&o.CharTrigrams)
+	return c
+}
+
+// Count counts the n-grams of all documents of the corpus using the
+// given number of concurrent workers. The n-grams of each document are
+// counted separately (n-grams do not cross document boundaries). Each
+// worker counts into its own local maps; the local maps are merged
+// after all documents have been counted. The result does not depend on
+// the number of workers and is the same as calling Add with the tokens
+// of each document. Broken documents are not counted at all. Errors
+// are handled and ordered in the same way as in Documents.
+func (c *Corpus) Count(ctx context.Context, workers int) (*Counts, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	// wctx stops the reading of new documents after the first error.
+	// Documents that are already being counted are counted with ctx,
+	// so their errors are not masked by the cancellation.
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan countJob)
+	locals := make([]*Counts, workers)
+	var mu sync.Mutex
+	var errs []countError
+	// Errors are ordered by 2*i for errors before the i-th document
+	// is counted and by 2*i+1 for errors of the i-th document.
+	addError := func(pos int, err error) {
+		mu.Lock()
+		errs = append(errs, countError{pos, err})
+		mu.Unlock()
+	}
+	var wg sync.WaitGroup
+	for i := range locals {
+		locals[i] = new(Counts)
+		wg.Add(1)
+		go func(counts *Counts) {
+			defer wg.Done()
+			for job := range jobs {
+				if err := job.count(ctx, counts); err != nil {
+					addError(2*job.index+1, documentError(ctx, job.name, err))
+					if !c.CollectErrors {
+						cancel()
+					}
+				}
+			}
+		}(locals[i])
+	}
+	var n int
+	err := c.documents(wctx, func(name string, format Format, r io.Reader) error {
+		bs, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		select {
+		case jobs <- countJob{index: n, name: name, format: format, content: bs}:
+			n++
+			return nil
+		case <-wctx.Done():
+			return wctx.Err()
+		}
+	}, func(err error) {
+		addError(2*n, err)
+	})
+	close(jobs)
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil && wctx.Err() == nil {
+		addError(2*n, err)
+	}
+	sort.Stable(countErrors(errs))
+	if !c.CollectErrors && len(errs) > 0 {
+		return nil, errs[0].err
+	}
+	counts := new(Counts)
+	for _, local := range locals {
+		counts.Append(local)
+	}
+	if len(errs) > 0 {
+		es := make(Errors, len(errs))
+		for i, e := range errs {
+			es[i] = e.err
+		}
+		return counts, es
+	}
+	return counts, nil
+}
+
+type countJob struct {
+	index   int
+	name    string
+	format  Format
+	content []byte
+}
+
+type countError struct {
+	pos int
+	err error
+}
+
+// countErrors sorts errors by their position.
+type countErrors []countError
+
+func (es countErrors) Len() int           { return len(es) }
+func (es countErrors) Less(i, j int) bool { return es[i].pos < es[j].pos }
+func (es countErrors) Swap(i, j int)      { es[i], es[j] = es[j], es[i] }
+
+// count reads all tokens of the document and adds them to the counts.
+func (job countJob) count(ctx context.Context, counts *Counts) error {
+	var ts []string
+	err := job.format.NewTokener(bytes.NewReader(job.content)).TokensContext(ctx,
+		func(t Token) error {
+			ts = append(ts, string(t))
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	counts.Add(ts...)
+	return nil
+}
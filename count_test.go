@@ -0,0 +1,139 @@
+package corpus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountsAdd(t *testing.T) {
+	var c Counts
+	c.Add("a", "b", "a")
+	tests := []struct {
+		name      string
+		got, want uint64
+	}{
+		{"unigram a", c.Unigrams.Get("a"), 2},
+		{"bigram a b", c.Bigrams.Get("a").Get("b"), 1},
+		{"trigram a b a", c.Trigrams.Get("a").Get("b").Get("a"), 1},
+		{"unigram total", c.Unigrams.Total(), 3},
+		{"bigram total", c.Bigrams.Total(), 2},
+		{"trigram total", c.Trigrams.Total(), 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("expected %d; got %d", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestCountsAppend(t *testing.T) {
+	var a, b, want Counts
+	a.Add("a", "b", "c")
+	b.Add("b", "c", "d")
+	want.Add("a", "b", "c")
+	want.Add("b", "c", "d")
+	if got := a.Append(&b); !reflect.DeepEqual(*got, want) {
+		t.Fatalf("expected %v; got %v", want, *got)
+	}
+}
+
+func TestCorpusCount(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	c := NewCorpus(filepath.Join(dir, "a"), filepath.Join(dir, "b/page.xml.gz"))
+	var want Counts
+	for _, path := range []string{"a/dta.xml", "a/text.txt", "b/page.xml.gz"} {
+		var ts []string
+		err := NewCorpus(filepath.Join(dir, path)).Tokens(context.Background(),
+			func(_ string, t Token) error {
+				ts = append(ts, string(t))
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		want.Add(ts...)
+	}
+	for _, workers := range []int{0, 1, 2, 4, 8} {
+		t.Run(fmt.Sprintf("%d", workers), func(t *testing.T) {
+			got, err := c.Count(context.Background(), workers)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Fatalf("expected %v; got %v", want, *got)
+			}
+		})
+	}
+}
+
+func TestCorpusCountErrors(t *testing.T) {
+	dir := newTestCorpusDir(t)
+	defer os.RemoveAll(dir)
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("collect %d", workers), func(t *testing.T) {
+			c := NewCorpus(dir)
+			c.CollectErrors = true
+			got, err := c.Count(context.Background(), workers)
+			errs, ok := err.(Errors)
+			if !ok || len(errs) != 2 {
+				t.Fatalf("expected 2 errors; got %v", err)
+			}
+			for i, name := range []string{"b/broken.xml", "c/unknown.bin"} {
+				derr, ok := errs[i].(*DocumentError)
+				if !ok || derr.Name != filepath.Join(dir, name) {
+					t.Fatalf("invalid error: %v", errs[i])
+				}
+			}
+			if got == nil || got.Unigrams.Total() != 31 {
+				t.Fatalf("expected 31 tokens; got %v", got)
+			}
+		})
+		t.Run(fmt.Sprintf("stop %d", workers), func(t *testing.T) {
+			_, err := NewCorpus(dir).Count(context.Background(), workers)
+			derr, ok := err.(*DocumentError)
+			if !ok || derr.Name != filepath.Join(dir, "b/broken.xml") {
+				t.Fatalf("invalid error: %v", err)
+			}
+		})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewCorpus(dir).Count(ctx, 4); err != context.Canceled {
+		t.Fatalf("expected %v; got %v", context.Canceled, err)
+	}
+}
+
+func TestCorpusCountStopRace(t *testing.T) {
+	// A broken document must not cancel the counting of the
+	// documents before it: Count has to return the error of the
+	// broken document and not context.Canceled.
+	dir, err := ioutil.TempDir("", "corpus")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	text := []byte(strings.Repeat("a b c\n", 1<<12))
+	for name, content := range map[string][]byte{
+		"a.txt": text, "b.xml": []byte("<TEI><text>"), "c.txt": text,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		_, err := NewCorpus(dir).Count(context.Background(), 4)
+		derr, ok := err.(*DocumentError)
+		if !ok || derr.Name != filepath.Join(dir, "b.xml") {
+			t.Fatalf("invalid error: %v", err)
+		}
+	}
+}
@@ -1,51 +1,137 @@
 package corpus
 
 import (
+	"context"
 	"encoding/xml"
 	"io"
-
-	"github.com/pkg/errors"
 )
 
+// DTA reads tokens from DTA corpus files.
+// The tokens are split in the same way as DTAReadTokens splits them.
+type DTA struct {
+	r io.Reader
+}
+
+// NewDTA creates a new DTA reader that reads from the given reader.
+func NewDTA(r io.Reader) *DTA {
+	return &DTA{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (dta *DTA) Tokens(f func(Token)) error {
+	return DTAReadTokens(dta.r, f)
+}
+
+// TokensContext implements the ContextTokener interface.
+func (dta *DTA) TokensContext(ctx context.Context, f TokenFunc) error {
+	return DTAReadTokensContext(ctx, dta.r, f)
+}
+
 // DTAReadTokensAndClose is a conveniece function that reads
 // all tokens in a DTA file and closes the reader.
-func DTAReadTokensAndClose(r io.ReadCloser, f func(Token)) (err error) {
-	defer func() {
-		e2 := r.Close()
-		if e2 != nil && err == nil {
-			err = e2
-		}
-	}()
-	err = DTAReadTokens(r, f)
-	return
+func DTAReadTokensAndClose(r io.ReadCloser, f func(Token)) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadTokens(r, f)
+	})
 }
 
 // DTAReadTokens reads all tokens form a DTA corpus file.
 func DTAReadTokens(r io.Reader, f func(Token)) error {
-	d := xml.NewDecoder(r)
-	var err error
-	var t xml.Token
-	var inToken bool
-	for t, err = d.Token(); err == nil; t, err = d.Token() {
+	return DTAReadTokensContext(context.Background(), r, TokenFuncOf(f))
+}
+
+// DTAReadTokensAndCloseContext is a conveniece function that reads
+// all tokens in a DTA file and closes the reader.
+func DTAReadTokensAndCloseContext(ctx context.Context, r io.ReadCloser, f TokenFunc) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadTokensContext(ctx, r, f)
+	})
+}
+
+// DTAReadTokensContext reads all tokens form a DTA corpus file.
+// Reading stops if the context is canceled or if the callback
+// function returns an error. If the context is canceled, ctx.Err()
+// is returned. Errors of the callback function are returned as they
+// are, except for ErrStop, for which nil is returned. Read and
+// syntax errors are wrapped (use errors.Cause to get the underlying
+// *xml.SyntaxError).
+func DTAReadTokensContext(ctx context.Context, r io.Reader, f TokenFunc) error {
+	d := newDTADecoder(contextReader{ctx: ctx, r: r})
+	f = withContext(ctx, f)
+	for {
+		str, err := d.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapContext(ctx, err, "invalid dta corpus file")
+		}
+		if err := tokenize(str, f); err != nil {
+			return stopped(err)
+		}
+	}
+}
+
+// dtaDecoder decodes the character data of the token elements.
+type dtaDecoder struct {
+	d       *xml.Decoder
+	inToken bool
+}
+
+func newDTADecoder(r io.Reader) *dtaDecoder {
+	return &dtaDecoder{d: xml.NewDecoder(r)}
+}
+
+// next returns the character data of the next token element.
+// It returns io.EOF if there are no more tokens.
+func (d *dtaDecoder) next() (string, error) {
+	for {
+		t, err := d.d.Token()
+		if err != nil {
+			return "", err
+		}
 		switch tt := t.(type) {
 		case xml.CharData:
-			if inToken {
-				tokenize(string(tt), f)
+			if d.inToken {
+				return string(tt), nil
 			}
 		case xml.StartElement:
-			inToken = tt.Name.Local == "token"
+			d.inToken = tt.Name.Local == "token"
 		case xml.EndElement:
-			inToken = false
+			d.inToken = false
 		}
 	}
-	if err == io.EOF {
-		return nil
-	}
-	return errors.Wrapf(err, "invalid dta corpus file")
 }
 
-func tokenize(t string, f func(Token)) {
+// readAndClose calls the given function with the reader
+// and closes the reader afterwards.
+func readAndClose(r io.ReadCloser, f func(io.Reader) error) (err error) {
+	defer func() {
+		e2 := r.Close()
+		if e2 != nil && err == nil {
+			err = e2
+		}
+	}()
+	err = f(r)
+	return
+}
+
+func tokenize(t string, f TokenFunc) error {
 	for _, s := range new(splitter).split(t) {
-		f(Token(s))
+		if err := f(Token(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xmlAttr returns the value of the attribute with the given
+// local name or the empty string if the attribute does not exist.
+func xmlAttr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
 	}
+	return ""
 }
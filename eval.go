@@ -0,0 +1,133 @@
+package corpus
+
+import (
+	"context"
+	"math"
+)
+
+// Evaluation holds the evaluation of a language model on a sequence
+// of tokens. Unknown tokens (OOVs) and known tokens with a probability
+// of 0 are counted but do not contribute to the log probability, the
+// perplexity and the cross-entropy.
+type Evaluation struct {
+	LogProb   float64 // log10 probability of the known tokens
+	Tokens    int     // number of tokens
+	OOVs      int     // number of unknown tokens
+	ZeroProbs int     // number of known tokens with a probability of 0
+}
+
+// Add adds another evaluation to this.
+func (e *Evaluation) Add(o Evaluation) *Evaluation {
+	e.LogProb += o.LogProb
+	e.Tokens += o.Tokens
+	e.OOVs += o.OOVs
+	e.ZeroProbs += o.ZeroProbs
+	return e
+}
+
+// Perplexity returns the perplexity of the known tokens. Tokens with
+// a probability of 0 are not included. It returns 0 if there are no
+// such tokens.
+func (e Evaluation) Perplexity() float64 {
+	n := e.scored()
+	if n == 0 {
+		return 0
+	}
+	return math.Pow(10, -e.LogProb/float64(n))
+}
+
+// CrossEntropy returns the cross-entropy of the known tokens in bits
+// per token. Tokens with a probability of 0 are not included. It
+// returns 0 if there are no such tokens.
+func (e Evaluation) CrossEntropy() float64 {
+	n := e.scored()
+	if n == 0 {
+		return 0
+	}
+	return -e.LogProb * math.Log2(10) / float64(n)
+}
+
+// scored returns the number of tokens that are included in LogProb.
+func (e Evaluation) scored() int {
+	return e.Tokens - e.OOVs - e.ZeroProbs
+}
+
+// OOVRate returns the ratio of unknown tokens.
+func (e Evaluation) OOVRate() float64 {
+	if e.Tokens == 0 {
+		return 0
+	}
+	return float64(e.OOVs) / float64(e.Tokens)
+}
+
+// Evaluator evaluates a language model on sentences or token streams.
+// The context of the model is reset at the start of each sentence.
+type Evaluator struct {
+	Total     Evaluation   // evaluation of all sentences
+	Sentences []Evaluation // evaluation of each sentence
+
+	model Model
+}
+
+// NewEvaluator creates a new evaluator for the given model.
+func NewEvaluator(m Model) *Evaluator {
+	return &Evaluator{model: m}
+}
+
+// Sentence evaluates the given sentence and returns its evaluation.
+func (e *Evaluator) Sentence(s []Token) Evaluation {
+	var se sentenceEvaluation
+	for _, t := range s {
+		se.add(e.model, t)
+	}
+	return e.add(se.eval)
+}
+
+// Tokens evaluates all tokens of the Tokener as one sentence.
+func (e *Evaluator) Tokens(t Tokener) error {
+	return e.TokensContext(context.Background(), ContextTokenerOf(t))
+}
+
+// TokensContext evaluates all tokens of the ContextTokener as one
+// sentence. If an error occurs, nothing is added to the evaluator.
+func (e *Evaluator) TokensContext(ctx context.Context, t ContextTokener) error {
+	var se sentenceEvaluation
+	err := t.TokensContext(ctx, func(t Token) error {
+		se.add(e.model, t)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	e.add(se.eval)
+	return nil
+}
+
+func (e *Evaluator) add(eval Evaluation) Evaluation {
+	e.Sentences = append(e.Sentences, eval)
+	e.Total.Add(eval)
+	return eval
+}
+
+// sentenceEvaluation evaluates the tokens of one sentence using the
+// last Order()-1 tokens as context.
+type sentenceEvaluation struct {
+	eval Evaluation
+	ctx  []string
+}
+
+func (s *sentenceEvaluation) add(m Model, t Token) {
+	w := string(t)
+	s.eval.Tokens++
+	if !m.Known(w) {
+		s.eval.OOVs++
+	} else if p := m.Prob(w, s.ctx...); p > 0 {
+		s.eval.LogProb += math.Log10(p)
+	} else {
+		s.eval.ZeroProbs++
+	}
+	s.ctx = append(s.ctx, w)
+	if n := m.Order() - 1; len(s.ctx) > n {
+		s.ctx = s.ctx[len(s.ctx)-n:]
+	}
+}
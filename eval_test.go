@@ -0,0 +1,103 @@
+package corpus
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvaluatorSentence(t *testing.T) {
+	e := NewEvaluator(newTestLanguageModel(AddK, 1))
+	got := e.Sentence([]Token{"the", "zebra", "cat"})
+	// P(the) = (6+1)/48, P(cat) = (3+1)/48
+	want := Evaluation{LogProb: math.Log10(7.0/48) + math.Log10(4.0/48), Tokens: 3, OOVs: 1}
+	if math.Abs(got.LogProb-want.LogProb) > 1e-12 || got.Tokens != want.Tokens || got.OOVs != want.OOVs {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if p, want := got.Perplexity(), math.Sqrt(48.0*48/28); math.Abs(p-want) > 1e-9 {
+		t.Fatalf("expected %g; got %g", want, p)
+	}
+	if h, want := got.CrossEntropy(), math.Log2(math.Sqrt(48.0*48/28)); math.Abs(h-want) > 1e-9 {
+		t.Fatalf("expected %g; got %g", want, h)
+	}
+	if r := got.OOVRate(); r != 1.0/3 {
+		t.Fatalf("expected %g; got %g", 1.0/3, r)
+	}
+	e.Sentence(nil)
+	if len(e.Sentences) != 2 || e.Total != got {
+		t.Fatalf("invalid evaluator: %v", e)
+	}
+}
+
+func TestEvaluatorContext(t *testing.T) {
+	m := newTestLanguageModel(KneserNey, 3)
+	e := NewEvaluator(m)
+	got := e.Sentence([]Token{"the", "dog", "sat", "on"})
+	want := math.Log10(m.Prob("the")) + math.Log10(m.Prob("dog", "the")) +
+		math.Log10(m.Prob("sat", "the", "dog")) + math.Log10(m.Prob("on", "dog", "sat"))
+	if math.Abs(got.LogProb-want) > 1e-12 {
+		t.Fatalf("expected %g; got %g", want, got.LogProb)
+	}
+}
+
+func TestEvaluatorTokens(t *testing.T) {
+	e := NewEvaluator(newTestLanguageModel(WittenBell, 2))
+	if err := e.Tokens(NewText(strings.NewReader("the cat\nsat on the zebra"))); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	want := NewEvaluator(newTestLanguageModel(WittenBell, 2)).Sentence(
+		[]Token{"the", "cat", "sat", "on", "the", "zebra"})
+	if len(e.Sentences) != 1 || e.Total != want {
+		t.Fatalf("expected %v; got %v", want, e.Total)
+	}
+	if err := e.Tokens(NewTEI(strings.NewReader("<TEI><text>"))); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+	if len(e.Sentences) != 1 {
+		t.Fatalf("expected 1 sentence; got %d", len(e.Sentences))
+	}
+}
+
+func TestEvaluatorDTASentences(t *testing.T) {
+	e := NewEvaluator(newTestLanguageModel(GoodTuring, 3))
+	err := DTAReadSentencesAndClose(openDTATestFile(t), func(s []Token) {
+		e.Sentence(s)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(e.Sentences) != 2 {
+		t.Fatalf("expected 2 sentences; got %d", len(e.Sentences))
+	}
+	if e.Total.Tokens != 22 || e.Total.OOVs != 21 {
+		t.Fatalf("invalid evaluation: %v", e.Total)
+	}
+}
+
+func TestEvaluatorZeroProbs(t *testing.T) {
+	m := newTestLanguageModel(AddK, 2)
+	m.K = 0
+	got := NewEvaluator(m).Sentence([]Token{"the", "cat", "red", "zebra"})
+	logProb := math.Log10(m.Prob("the")) + math.Log10(m.Prob("cat", "the"))
+	if m.Prob("red", "cat") != 0 {
+		t.Fatalf("expected P(red|cat) = 0; got %g", m.Prob("red", "cat"))
+	}
+	want := Evaluation{LogProb: logProb, Tokens: 4, OOVs: 1, ZeroProbs: 1}
+	if math.Abs(got.LogProb-want.LogProb) > 1e-12 || got.Tokens != want.Tokens ||
+		got.OOVs != want.OOVs || got.ZeroProbs != want.ZeroProbs {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if p, want := got.Perplexity(), math.Pow(10, -logProb/2); math.Abs(p-want) > 1e-9 {
+		t.Fatalf("expected %g; got %g", want, p)
+	}
+	if h := got.CrossEntropy(); math.IsInf(h, 0) || math.IsNaN(h) {
+		t.Fatalf("invalid cross-entropy: %g", h)
+	}
+}
+
+func TestEvaluationEmpty(t *testing.T) {
+	var e Evaluation
+	if e.Perplexity() != 0 || e.CrossEntropy() != 0 || e.OOVRate() != 0 {
+		t.Fatalf("invalid empty evaluation: %v", e)
+	}
+}
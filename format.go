@@ -0,0 +1,108 @@
+package corpus
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Format represents the format of a corpus document.
+type Format int
+
+// The different corpus document formats.
+const (
+	UnknownFormat Format = iota
+	DTAFormat            // D-Spin TCF
+	TEIFormat            // TEI-P5
+	TextFormat           // plain UTF-8 text
+	HOCRFormat
+	PAGEFormat
+	ALTOFormat
+	VRTFormat
+	CoNLLUFormat
+)
+
+var formatNames = [...]string{
+	UnknownFormat: "unknown",
+	DTAFormat:     "dta",
+	TEIFormat:     "tei",
+	TextFormat:    "text",
+	HOCRFormat:    "hocr",
+	PAGEFormat:    "page",
+	ALTOFormat:    "alto",
+	VRTFormat:     "vrt",
+	CoNLLUFormat:  "conllu",
+}
+
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return formatNames[UnknownFormat]
+	}
+	return formatNames[f]
+}
+
+// NewTokener returns a new ContextTokener for the format
+// that reads from the given reader. It returns nil if
+// the format is unknown.
+func (f Format) NewTokener(r io.Reader) ContextTokener {
+	switch f {
+	case DTAFormat:
+		return NewDTA(r)
+	case TEIFormat:
+		return NewTEI(r)
+	case TextFormat:
+		return NewText(r)
+	case HOCRFormat:
+		return NewHOCR(r)
+	case PAGEFormat:
+		return NewPAGE(r)
+	case ALTOFormat:
+		return NewALTO(r)
+	case VRTFormat:
+		return NewVRT(r)
+	case CoNLLUFormat:
+		return NewCoNLLU(r)
+	}
+	return nil
+}
+
+// formatExts maps file extensions to formats.
+var formatExts = map[string]Format{
+	".txt":    TextFormat,
+	".text":   TextFormat,
+	".vrt":    VRTFormat,
+	".conllu": CoNLLUFormat,
+	".hocr":   HOCRFormat,
+}
+
+// formatRoots maps XML root elements to formats.
+var formatRoots = map[string]Format{
+	"D-Spin": DTAFormat,
+	"TEI":    TEIFormat,
+	"html":   HOCRFormat,
+	"PcGts":  PAGEFormat,
+	"alto":   ALTOFormat,
+}
+
+// DetectFormat detects the format of a document using the extension
+// of its name and the first bytes of its content. The extensions
+// .txt, .text, .vrt, .conllu and .hocr are recognized. XML documents
+// are detected by their root element.
+func DetectFormat(name string, head []byte) Format {
+	if f, ok := formatExts[strings.ToLower(filepath.Ext(name))]; ok {
+		return f
+	}
+	d := xml.NewDecoder(bytes.NewReader(head))
+	d.Strict = false
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return UnknownFormat
+		}
+		if e, ok := t.(xml.StartElement); ok {
+			return formatRoots[e.Name.Local]
+		}
+	}
+}
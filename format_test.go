@@ -0,0 +1,49 @@
+package corpus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name, head string
+		want       Format
+	}{
+		{"a.txt", "<TEI>", TextFormat},
+		{"a.TXT", "", TextFormat},
+		{"a.vrt", "<doc>", VRTFormat},
+		{"a.conllu", "# sent_id = 1", CoNLLUFormat},
+		{"a.xml", `<?xml version="1.0"?><D-Spin xmlns="http://www.dspin.de/data">`, DTAFormat},
+		{"a.xml", `<?xml version="1.0"?><!-- comment --><TEI>`, TEIFormat},
+		{"a.html", `<!DOCTYPE html><html><body class="ocr_page">`, HOCRFormat},
+		{"a.xml", `<PcGts xmlns="http://schema.primaresearch.org/PAGE/gts/pagecontent/2019-07-15">`, PAGEFormat},
+		{"a.xml", `<alto xmlns="http://www.loc.gov/standards/alto/ns-v3#">`, ALTOFormat},
+		{"a.xml", `<unknown/>`, UnknownFormat},
+		{"a.bin", "\x00\x01", UnknownFormat},
+		{"a", "plain text", UnknownFormat},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+" "+tc.head, func(t *testing.T) {
+			if got := DetectFormat(tc.name, []byte(tc.head)); got != tc.want {
+				t.Fatalf("expected %s; got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatNewTokener(t *testing.T) {
+	for f := DTAFormat; f <= CoNLLUFormat; f++ {
+		t.Run(f.String(), func(t *testing.T) {
+			if f.NewTokener(strings.NewReader("")) == nil {
+				t.Fatalf("expected a tokener; got nil")
+			}
+		})
+	}
+	if got := UnknownFormat.NewTokener(strings.NewReader("")); got != nil {
+		t.Fatalf("expected nil; got %v", got)
+	}
+	if got := Format(-1).String(); got != "unknown" {
+		t.Fatalf("expected unknown; got %s", got)
+	}
+}
@@ -1,3 +1,5 @@
-module "github.com/finkf/corpus"
+module github.com/finkf/corpus
 
-require "github.com/pkg/errors" v0.8.0
+go 1.12
+
+require github.com/pkg/errors v0.8.0
@@ -15,3 +15,57 @@ func openDTATestFile(t *testing.T) io.ReadCloser {
 	}
 	return is
 }
+
+func openTEITestFile(t *testing.T) io.ReadCloser {
+	is, err := os.Open("testdata/tei.xml")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return is
+}
+
+func openTestFile(t *testing.T, path string) io.ReadCloser {
+	is, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return is
+}
+
+// checkUnigrams checks that the total count of the unigrams is
+// consistent with their entries.
+func checkUnigrams(t *testing.T, u *Unigrams) {
+	var total uint64
+	u.Each(func(_ string, c uint64) { total += c })
+	if total != u.Total() {
+		t.Fatalf("inconsistent unigrams: expected %d; got %d", total, u.Total())
+	}
+}
+
+func checkBigrams(t *testing.T, b *Bigrams) {
+	var total uint64
+	b.Each(func(_ string, u *Unigrams) {
+		checkUnigrams(t, u)
+		if u.Len() == 0 {
+			t.Fatalf("empty context")
+		}
+		total += u.Total()
+	})
+	if total != b.Total() {
+		t.Fatalf("inconsistent bigrams: expected %d; got %d", total, b.Total())
+	}
+}
+
+func checkTrigrams(t *testing.T, tr *Trigrams) {
+	var total uint64
+	tr.Each(func(_ string, b *Bigrams) {
+		checkBigrams(t, b)
+		if b.Len() == 0 {
+			t.Fatalf("empty context")
+		}
+		total += b.Total()
+	})
+	if total != tr.Total() {
+		t.Fatalf("inconsistent trigrams: expected %d; got %d", total, tr.Total())
+	}
+}
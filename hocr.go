@@ -0,0 +1,151 @@
+package corpus
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HOCR reads tokens from hOCR files. The text of each ocrx_word
+// element is split in the same way as DTAReadTokens splits tokens.
+type HOCR struct {
+	r io.Reader
+}
+
+// NewHOCR creates a new hOCR reader that reads from the given reader.
+func NewHOCR(r io.Reader) *HOCR {
+	return &HOCR{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (h *HOCR) Tokens(f func(Token)) error {
+	return h.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface.
+func (h *HOCR) TokensContext(ctx context.Context, f TokenFunc) error {
+	return h.OCRTokensContext(ctx, func(t OCRToken) error {
+		return f(t.Token)
+	})
+}
+
+// OCRTokens reads all tokens with their positions.
+func (h *HOCR) OCRTokens(f func(OCRToken)) error {
+	return h.OCRTokensContext(context.Background(), func(t OCRToken) error {
+		f(t)
+		return nil
+	})
+}
+
+// OCRTokensContext reads all tokens with their positions. It handles
+// cancellation and errors in the same way as DTAReadTokensContext.
+func (h *HOCR) OCRTokensContext(ctx context.Context, f func(OCRToken) error) error {
+	d := xml.NewDecoder(contextReader{ctx: ctx, r: h.r})
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	var page, region, line string
+	var word *OCRToken
+	var text bytes.Buffer
+	var depth, wdepth int
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapContext(ctx, err, "invalid hocr file")
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			depth++
+			if word != nil {
+				continue
+			}
+			title := xmlAttr(tt, "title")
+			for _, class := range strings.Fields(xmlAttr(tt, "class")) {
+				switch class {
+				case "ocr_page":
+					page, region, line = xmlAttr(tt, "id"), "", ""
+				case "ocr_carea":
+					region = xmlAttr(tt, "id")
+				case "ocr_line", "ocrx_line", "ocr_header", "ocr_caption", "ocr_textfloat":
+					line = xmlAttr(tt, "id")
+				case "ocrx_word":
+					word = &OCRToken{
+						Page:   page,
+						Region: region,
+						Line:   line,
+						Box:    hocrBox(title),
+						Conf:   hocrFloat(title, "x_wconf") / 100,
+					}
+					wdepth = depth
+					text.Reset()
+				}
+			}
+		case xml.CharData:
+			if word != nil {
+				text.Write(tt)
+			}
+		case xml.EndElement:
+			if word != nil && depth == wdepth {
+				if err := tokenizeOCRWord(ctx, *word, text.String(), f); err != nil {
+					return stopped(err)
+				}
+				word = nil
+			}
+			depth--
+		}
+	}
+}
+
+// tokenizeOCRWord splits the text of an OCR word and calls the
+// callback function for each token with the position of the word.
+func tokenizeOCRWord(ctx context.Context, w OCRToken, text string, f func(OCRToken) error) error {
+	for _, str := range strings.Fields(text) {
+		err := tokenize(str, withContext(ctx, func(t Token) error {
+			w.Token = t
+			return f(w)
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hocrProperty returns the values of the given property
+// in an hOCR title attribute.
+func hocrProperty(title, key string) []string {
+	for _, p := range strings.Split(title, ";") {
+		fields := strings.Fields(p)
+		if len(fields) > 0 && fields[0] == key {
+			return fields[1:]
+		}
+	}
+	return nil
+}
+
+func hocrBox(title string) Box {
+	vals := hocrProperty(title, "bbox")
+	if len(vals) != 4 {
+		return Box{}
+	}
+	var b Box
+	for i, p := range []*int{&b.X0, &b.Y0, &b.X1, &b.Y1} {
+		*p, _ = strconv.Atoi(vals[i])
+	}
+	return b
+}
+
+func hocrFloat(title, key string) float64 {
+	vals := hocrProperty(title, key)
+	if len(vals) != 1 {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(vals[0], 64)
+	return f
+}
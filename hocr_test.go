@@ -0,0 +1,53 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHOCROCRTokens(t *testing.T) {
+	tests := []struct {
+		i    int
+		want OCRToken
+	}{
+		{0, OCRToken{Token: "D", Box: Box{100, 100, 160, 140}, Conf: .91, Page: "page_1", Region: "block_1_1", Line: "line_1_1"}},
+		{1, OCRToken{Token: ".", Box: Box{100, 100, 160, 140}, Conf: .91, Page: "page_1", Region: "block_1_1", Line: "line_1_1"}},
+		{2, OCRToken{Token: "Henrici", Box: Box{180, 100, 400, 140}, Conf: .87, Page: "page_1", Region: "block_1_1", Line: "line_1_1"}},
+		{4, OCRToken{Token: "&", Box: Box{420, 100, 700, 140}, Conf: .45, Page: "page_1", Region: "block_1_1", Line: "line_1_1"}},
+		{6, OCRToken{Token: ",", Box: Box{100, 160, 300, 200}, Conf: .96, Page: "page_1", Region: "block_1_1", Line: "line_1_2"}},
+		{7, OCRToken{Token: "Wohlerfahrner", Box: Box{100, 100, 300, 140}, Conf: 0, Page: "page_2", Line: "line_2_1"}},
+	}
+	r := openTestFile(t, "testdata/hocr.html")
+	defer r.Close()
+	var ts []OCRToken
+	if err := NewHOCR(r).OCRTokens(func(t OCRToken) { ts = append(ts, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(ts) != 8 {
+		t.Fatalf("expected 8 tokens; got %d", len(ts))
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.i), func(t *testing.T) {
+			if got := ts[tc.i]; !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHOCRTokens(t *testing.T) {
+	html := `<html><body><div class='ocr_page'><span class='ocr_line'>` +
+		`<span class='ocrx_word'>a,b<br></span> <span class='ocrx_word'>c&nbsp;d</span>` +
+		`</span></div></body></html>`
+	want := []Token{"a", ",", "b", "c", "d"}
+	var tokener Tokener = NewHOCR(strings.NewReader(html))
+	var got []Token
+	if err := tokener.Tokens(func(t Token) { got = append(got, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
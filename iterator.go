@@ -0,0 +1,86 @@
+package corpus
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// TokenIterator is a pull style iterator over the tokens of a corpus
+// file. The tokens are split in the same way as DTAReadTokens splits them.
+//
+//	i := NewDTATokenIterator(r)
+//	for i.Next() {
+//		use(i.Token())
+//	}
+//	if err := i.Err(); err != nil {
+//		...
+//	}
+type TokenIterator struct {
+	read func() (string, error)
+	buf  []string
+	tok  Token
+	err  error
+}
+
+// NewDTATokenIterator returns a new token iterator over a DTA corpus file.
+func NewDTATokenIterator(r io.Reader) *TokenIterator {
+	d := newDTADecoder(r)
+	return &TokenIterator{read: func() (string, error) {
+		str, err := d.next()
+		if err != nil && err != io.EOF {
+			return "", errors.Wrapf(err, "invalid dta corpus file")
+		}
+		return str, err
+	}}
+}
+
+// NewTextTokenIterator returns a new token iterator over a plain
+// UTF-8 text. The text is split at white space.
+func NewTextTokenIterator(r io.Reader) *TokenIterator {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineLength)
+	s.Split(bufio.ScanWords)
+	return &TokenIterator{read: func() (string, error) {
+		if s.Scan() {
+			return s.Text(), nil
+		}
+		if err := s.Err(); err != nil {
+			return "", errors.Wrapf(err, "cannot read text")
+		}
+		return "", io.EOF
+	}}
+}
+
+// Next advances the iterator to the next token. It returns false
+// if there are no more tokens or if an error occurred.
+func (i *TokenIterator) Next() bool {
+	for len(i.buf) == 0 {
+		if i.err != nil {
+			return false
+		}
+		str, err := i.read()
+		if err != nil {
+			i.err = err
+			return false
+		}
+		i.buf = new(splitter).split(str)
+	}
+	i.tok = Token(i.buf[0])
+	i.buf = i.buf[1:]
+	return true
+}
+
+// Token returns the current token.
+func (i *TokenIterator) Token() Token {
+	return i.tok
+}
+
+// Err returns the first error that occurred during the iteration.
+func (i *TokenIterator) Err() error {
+	if i.err == io.EOF {
+		return nil
+	}
+	return i.err
+}
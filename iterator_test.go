@@ -0,0 +1,98 @@
+package corpus
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+)
+
+func TestDTATokenIterator(t *testing.T) {
+	r := openDTATestFile(t)
+	defer r.Close()
+	var want []Token
+	if err := DTAReadTokensAndClose(openDTATestFile(t), func(t Token) {
+		want = append(want, t)
+	}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var got []Token
+	i := NewDTATokenIterator(r)
+	for i.Next() {
+		got = append(got, i.Token())
+	}
+	if err := i.Err(); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if i.Next() {
+		t.Fatalf("expected Next() to return false")
+	}
+}
+
+func TestTextTokenIterator(t *testing.T) {
+	tests := []struct {
+		test string
+		want []Token
+	}{
+		{"", nil},
+		{" \n ", nil},
+		{"D. Henrici Caſparis\nAbelii,", []Token{"D", ".", "Henrici", "Caſparis", "Abelii", ","}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.test, func(t *testing.T) {
+			var got []Token
+			i := NewTextTokenIterator(strings.NewReader(tc.test))
+			for i.Next() {
+				got = append(got, i.Token())
+			}
+			if err := i.Err(); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTokenIteratorZip(t *testing.T) {
+	r := openDTATestFile(t)
+	defer r.Close()
+	a := NewDTATokenIterator(r)
+	b := NewTextTokenIterator(strings.NewReader("D. Henrici Casparis"))
+	var got [][2]Token
+	for a.Next() && b.Next() {
+		got = append(got, [2]Token{a.Token(), b.Token()})
+	}
+	want := [][2]Token{{"D", "D"}, {".", "."}, {"Henrici", "Henrici"}, {"Caſparis", "Casparis"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestTokenIteratorErrors(t *testing.T) {
+	r := openDTATestFile(t)
+	defer r.Close()
+	tests := []struct {
+		name string
+		i    *TokenIterator
+		want error
+	}{
+		{"dta", NewDTATokenIterator(iotest.TimeoutReader(r)), iotest.ErrTimeout},
+		{"text", NewTextTokenIterator(iotest.TimeoutReader(strings.NewReader("a b"))), iotest.ErrTimeout},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for tc.i.Next() {
+			}
+			if got := errors.Cause(tc.i.Err()); got != tc.want {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
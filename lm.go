@@ -0,0 +1,423 @@
+package corpus
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// UNK is the token that represents unknown words in language models.
+const UNK = "<UNK>"
+
+// Model is the interface of n-gram language models.
+type Model interface {
+	// Order returns the order of the model.
+	Order() int
+	// Known returns true if the token is part of the vocabulary.
+	Known(w string) bool
+	// Prob returns the conditional probability P(w|ctx). Only the
+	// last Order()-1 tokens of the context are used.
+	Prob(w string, ctx ...string) float64
+}
+
+// Smoothing represents the smoothing method of a language model.
+type Smoothing int
+
+// The different smoothing methods.
+const (
+	AddK              Smoothing = iota // add-k (Laplace) smoothing
+	GoodTuring                         // Good-Turing discounting with Katz backoff
+	WittenBell                         // interpolated Witten-Bell smoothing
+	KneserNey                          // interpolated Kneser-Ney smoothing
+	ModifiedKneserNey                  // modified Kneser-Ney smoothing
+)
+
+var smoothingNames = [...]string{
+	AddK:              "add-k",
+	GoodTuring:        "good-turing",
+	WittenBell:        "witten-bell",
+	KneserNey:         "kneser-ney",
+	ModifiedKneserNey: "modified-kneser-ney",
+}
+
+func (s Smoothing) String() string {
+	if s < 0 || int(s) >= len(smoothingNames) {
+		return "unknown"
+	}
+	return smoothingNames[s]
+}
+
+// katzMax is the maximal count that is discounted by Good-Turing.
+const katzMax = 5
+
+// LanguageModel is a smoothed n-gram language model of order 1 to 3
+// over unigram, bigram and trigram counts. The maps are expected to
+// be counted over the same tokens. The vocabulary of the model
+// consists of all unigrams and UNK. Unknown tokens are mapped to UNK,
+// so the probabilities over the vocabulary sum to one for any context.
+type LanguageModel struct {
+	K float64 // constant of add-k smoothing (default 1, must not be negative)
+
+	smoothing Smoothing
+	order     int
+	vocab     uint64
+	unigrams  *Unigrams
+	bigrams   *Bigrams
+	trigrams  *Trigrams
+	levels    [4]lmLevel            // the counts of each order
+	kn        [4]knDiscounts        // Kneser-Ney discounts of each order
+	knMass    [4]map[string]float64 // Kneser-Ney discount mass of the contexts
+	gt        [4][]float64          // Good-Turing discounts of each order
+	katz      map[string]katzInfo   // Katz backoff weights of the contexts
+}
+
+// NewLanguageModel creates a new language model with the given
+// smoothing method. The order of the model is 3 if bigrams and
+// trigrams are given, 2 if only bigrams are given and 1 otherwise.
+// Trigrams without bigrams are ignored, since the trigram
+// probabilities back off to the bigram probabilities.
+func NewLanguageModel(s Smoothing, u *Unigrams, b *Bigrams, t *Trigrams) *LanguageModel {
+	m := &LanguageModel{K: 1, smoothing: s, order: 1, unigrams: u}
+	m.levels[1] = lmLevel{u: u}
+	if b != nil {
+		m.order = 2
+		m.bigrams = b
+		m.levels[2] = lmLevel{b: b}
+	}
+	if b != nil && t != nil {
+		m.order = 3
+		m.trigrams = t
+		m.levels[3] = lmLevel{t: t}
+	}
+	m.vocab = u.Len()
+	if u.Get(UNK) == 0 {
+		m.vocab++
+	}
+	switch s {
+	case GoodTuring:
+		for n := 1; n <= m.order; n++ {
+			m.gt[n] = goodTuringDiscounts(m.levels[n].countOfCounts(katzMax + 1))
+		}
+		m.katz = map[string]katzInfo{"": m.katzInfo(1, nil, u)}
+		for n := 2; n <= m.order; n++ {
+			m.levels[n].each(func(h []string, c *Unigrams) {
+				m.katz[strings.Join(h, "\x00")] = m.katzInfo(n, h, c)
+			})
+		}
+	case KneserNey, ModifiedKneserNey:
+		// Lower orders use continuation counts.
+		if m.order >= 2 {
+			m.levels[1] = lmLevel{u: b.continuations()}
+		}
+		if m.order >= 3 {
+			m.levels[2] = lmLevel{b: t.continuations()}
+		}
+		for n := 1; n <= m.order; n++ {
+			m.kn[n] = newKNDiscounts(m.levels[n].countOfCounts(4), s == ModifiedKneserNey)
+			m.knMass[n] = make(map[string]float64)
+			m.levels[n].each(func(h []string, c *Unigrams) {
+				var mass float64
+				c.Each(func(_ string, k uint64) {
+					mass += m.kn[n].of(k)
+				})
+				m.knMass[n][strings.Join(h, "\x00")] = mass
+			})
+		}
+	}
+	return m
+}
+
+// Order returns the order of the model.
+func (m *LanguageModel) Order() int {
+	return m.order
+}
+
+// Smoothing returns the smoothing method of the model.
+func (m *LanguageModel) Smoothing() Smoothing {
+	return m.smoothing
+}
+
+// Words returns the sorted vocabulary of the model including UNK.
+func (m *LanguageModel) Words() []string {
+	words := make([]string, 0, m.vocab)
+	m.unigrams.Each(func(w string, _ uint64) {
+		words = append(words, w)
+	})
+	if m.unigrams.Get(UNK) == 0 {
+		words = append(words, UNK)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Known returns true if the given token is part of the vocabulary.
+func (m *LanguageModel) Known(w string) bool {
+	return w == UNK || m.unigrams.Get(w) > 0
+}
+
+// Prob returns the conditional probability P(w|ctx). Only the last
+// Order()-1 tokens of the context are used. If the context is
+// shorter, the probability of the according lower order is returned.
+func (m *LanguageModel) Prob(w string, ctx ...string) float64 {
+	if len(ctx) > m.order-1 {
+		ctx = ctx[len(ctx)-m.order+1:]
+	}
+	h := make([]string, len(ctx))
+	for i, t := range ctx {
+		h[i] = m.word(t)
+	}
+	w = m.word(w)
+	n := len(h) + 1
+	switch m.smoothing {
+	case GoodTuring:
+		return m.katzProb(n, h, w)
+	case WittenBell:
+		return m.wittenBell(n, h, w)
+	case KneserNey, ModifiedKneserNey:
+		return m.kneserNey(n, h, w)
+	default:
+		return m.addK(n, h, w)
+	}
+}
+
+func (m *LanguageModel) word(w string) string {
+	if m.Known(w) {
+		return w
+	}
+	return UNK
+}
+
+// addK returns the add-k estimate. Negative values of K are treated
+// as 0. The estimate of unseen contexts is uniform, which for K = 0
+// is the limit of the estimate for K towards 0.
+func (m *LanguageModel) addK(n int, h []string, w string) float64 {
+	c := m.levels[n].next(h)
+	k := math.Max(m.K, 0)
+	if c.Total() == 0 {
+		return 1 / float64(m.vocab)
+	}
+	return (float64(c.Get(w)) + k) / (float64(c.Total()) + k*float64(m.vocab))
+}
+
+func (m *LanguageModel) wittenBell(n int, h []string, w string) float64 {
+	lower := 1 / float64(m.vocab)
+	if n > 1 {
+		lower = m.wittenBell(n-1, h[1:], w)
+	}
+	c := m.levels[n].next(h)
+	if c.Total() == 0 {
+		return lower
+	}
+	types := float64(c.Len())
+	return (float64(c.Get(w)) + types*lower) / (float64(c.Total()) + types)
+}
+
+func (m *LanguageModel) kneserNey(n int, h []string, w string) float64 {
+	lower := 1 / float64(m.vocab)
+	if n > 1 {
+		lower = m.kneserNey(n-1, h[1:], w)
+	}
+	c := m.levels[n].next(h)
+	if c.Total() == 0 {
+		return lower
+	}
+	gamma := m.knMass[n][strings.Join(h, "\x00")]
+	k := c.Get(w)
+	return (float64(k) - m.kn[n].of(k) + gamma*lower) / float64(c.Total())
+}
+
+func (m *LanguageModel) katzProb(n int, h []string, w string) float64 {
+	c := m.levels[n].next(h)
+	k := c.Get(w)
+	if n == 1 {
+		unseen := m.vocab - c.Len()
+		if unseen == 0 {
+			return float64(k) / float64(c.Total())
+		}
+		if k > 0 {
+			return m.gtDiscount(n, k) * float64(k) / float64(c.Total())
+		}
+		// The left over mass is distributed uniformly.
+		return m.katz[""].alpha / float64(unseen)
+	}
+	if c.Total() == 0 {
+		return m.katzProb(n-1, h[1:], w)
+	}
+	info := m.katz[strings.Join(h, "\x00")]
+	switch {
+	case info.ml:
+		return float64(k) / float64(c.Total())
+	case k > 0:
+		return m.gtDiscount(n, k) * float64(k) / float64(c.Total())
+	default:
+		return info.alpha * m.katzProb(n-1, h[1:], w)
+	}
+}
+
+// katzInfo holds the backoff weight of a context. For the empty
+// context of the unigrams it holds the left over mass. If the lower order
+// has no probability mass left for the unseen tokens of the context,
+// the maximum likelihood estimate is used instead.
+type katzInfo struct {
+	alpha float64
+	ml    bool
+}
+
+func (m *LanguageModel) katzInfo(n int, h []string, c *Unigrams) katzInfo {
+	var seen, lower float64
+	c.Each(func(w string, k uint64) {
+		seen += m.gtDiscount(n, k) * float64(k) / float64(c.Total())
+		if n > 1 {
+			lower += m.katzProb(n-1, h[1:], w)
+		}
+	})
+	if n == 1 {
+		return katzInfo{alpha: 1 - seen}
+	}
+	if 1-lower <= 1e-12 {
+		return katzInfo{ml: true}
+	}
+	return katzInfo{alpha: (1 - seen) / (1 - lower)}
+}
+
+func (m *LanguageModel) gtDiscount(n int, k uint64) float64 {
+	if k > katzMax {
+		return 1
+	}
+	return m.gt[n][k]
+}
+
+// goodTuringDiscounts returns the Katz discount ratios for the counts
+// 1 to katzMax. If the Good-Turing estimate is not valid for a count,
+// an absolute discount of 0.5 is used instead.
+func goodTuringDiscounts(n []float64) []float64 {
+	d := make([]float64, katzMax+1)
+	common := (katzMax + 1) * n[katzMax+1] / n[1]
+	for c := 1; c <= katzMax; c++ {
+		star := float64(c+1) * n[c+1] / n[c]
+		x := (star/float64(c) - common) / (1 - common)
+		if !validDiscount(x) || x >= 1 {
+			x = (float64(c) - .5) / float64(c)
+		}
+		d[c] = x
+	}
+	return d
+}
+
+// knDiscounts holds the Kneser-Ney discounts for the counts 1, 2
+// and 3 or more.
+type knDiscounts [4]float64
+
+// newKNDiscounts calculates the discounts from the count of counts.
+// If a modified discount is not valid, the simple discount is used.
+func newKNDiscounts(n []float64, modified bool) knDiscounts {
+	y := n[1] / (n[1] + 2*n[2])
+	if !validDiscount(y) {
+		y = .5
+	}
+	d := knDiscounts{0, y, y, y}
+	if !modified {
+		return d
+	}
+	for i := 1; i <= 3; i++ {
+		x := float64(i) - float64(i+1)*y*n[i+1]/n[i]
+		if validDiscount(x) && x < float64(i) {
+			d[i] = x
+		}
+	}
+	return d
+}
+
+func validDiscount(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0) && x > 0
+}
+
+// of returns the discount of the given count. The discount is never
+// larger than the count itself.
+func (d knDiscounts) of(k uint64) float64 {
+	if k == 0 {
+		return 0
+	}
+	i := k
+	if i > 3 {
+		i = 3
+	}
+	return math.Min(d[i], float64(k))
+}
+
+// lmLevel gives access to the counts of one order of a model.
+type lmLevel struct {
+	u *Unigrams
+	b *Bigrams
+	t *Trigrams
+}
+
+// next returns the counts of the tokens following the given context.
+func (l lmLevel) next(h []string) *Unigrams {
+	switch len(h) {
+	case 0:
+		return l.u
+	case 1:
+		return l.b.Get(h[0])
+	default:
+		return l.t.Get(h[0]).Get(h[1])
+	}
+}
+
+// each calls the callback function for each context of the level.
+func (l lmLevel) each(f func([]string, *Unigrams)) {
+	switch {
+	case l.t != nil:
+		l.t.Each(func(a string, b *Bigrams) {
+			b.Each(func(b string, u *Unigrams) {
+				f([]string{a, b}, u)
+			})
+		})
+	case l.b != nil:
+		l.b.Each(func(a string, u *Unigrams) {
+			f([]string{a}, u)
+		})
+	default:
+		f(nil, l.u)
+	}
+}
+
+// countOfCounts returns the number of n-grams that occur exactly
+// k times for k = 0 to max.
+func (l lmLevel) countOfCounts(max int) []float64 {
+	n := make([]float64, max+1)
+	l.each(func(_ []string, u *Unigrams) {
+		u.Each(func(_ string, k uint64) {
+			if k <= uint64(max) {
+				n[k]++
+			}
+		})
+	})
+	return n
+}
+
+// continuations returns the number of different tokens that precede
+// each token.
+func (b *Bigrams) continuations() *Unigrams {
+	res := new(Unigrams)
+	b.Each(func(_ string, u *Unigrams) {
+		u.Each(func(w string, _ uint64) {
+			res.Add(w)
+		})
+	})
+	return res
+}
+
+// continuations returns the number of different tokens that precede
+// each bigram.
+func (t *Trigrams) continuations() *Bigrams {
+	res := new(Bigrams)
+	t.Each(func(_ string, b *Bigrams) {
+		b.Each(func(v string, u *Unigrams) {
+			u.Each(func(w string, _ uint64) {
+				res.Add(v, w)
+			})
+		})
+	})
+	return res
+}
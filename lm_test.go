@@ -0,0 +1,169 @@
+package corpus
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+const lmTestText = "the cat sat on the mat . the dog sat on the cat . " +
+	"a cat and a dog sat on a mat . the mat was red . the dog was red too ."
+
+func newTestLanguageModel(s Smoothing, order int) *LanguageModel {
+	ts := strings.Fields(lmTestText)
+	var b *Bigrams
+	var t *Trigrams
+	if order > 1 {
+		b = new(Bigrams).Add(ts...)
+	}
+	if order > 2 {
+		t = new(Trigrams).Add(ts...)
+	}
+	return NewLanguageModel(s, new(Unigrams).Add(ts...), b, t)
+}
+
+func TestLanguageModelSumsToOne(t *testing.T) {
+	contexts := [][]string{
+		nil,
+		{"the"},
+		{"on", "the"},
+		{"the", "cat"},
+		{"unknown"},
+		{"unknown", "the"},
+		{"the", "unknown"},
+		{"mat", "sat"},
+		{"x", "y", "sat", "on"},
+	}
+	for _, s := range []Smoothing{AddK, GoodTuring, WittenBell, KneserNey, ModifiedKneserNey} {
+		for order := 1; order <= 3; order++ {
+			m := newTestLanguageModel(s, order)
+			if got := m.Order(); got != order {
+				t.Fatalf("expected %d; got %d", order, got)
+			}
+			for _, ctx := range contexts {
+				t.Run(fmt.Sprintf("%s %d %v", s, order, ctx), func(t *testing.T) {
+					var sum float64
+					for _, w := range m.Words() {
+						p := m.Prob(w, ctx...)
+						if p <= 0 || p > 1 {
+							t.Fatalf("invalid probability P(%s|%v) = %g", w, ctx, p)
+						}
+						sum += p
+					}
+					if math.Abs(sum-1) > 1e-9 {
+						t.Fatalf("expected 1; got %g", sum)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestLanguageModelProb(t *testing.T) {
+	tests := []struct {
+		s    Smoothing
+		w    string
+		ctx  []string
+		want float64
+	}{
+		// P(cat|the) = (2+1)/(6+13)
+		{AddK, "cat", []string{"the"}, 3.0 / 19},
+		// P(<UNK>) = 1/(35+13)
+		{AddK, "unknown", nil, 1.0 / 48},
+		// P(sat|the cat) = (1+1)/(2+13)
+		{AddK, "sat", []string{"the", "cat"}, 2.0 / 15},
+		// P(<UNK>) = (0+12/13)/(35+12)
+		{WittenBell, "unknown", nil, 12.0 / 13 / 47},
+		// P(<UNK>|unknown) = P(<UNK>)
+		{WittenBell, "unknown", []string{"unknown"}, 12.0 / 13 / 47},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %s %v", tc.s, tc.w, tc.ctx), func(t *testing.T) {
+			m := newTestLanguageModel(tc.s, 3)
+			if got := m.Prob(tc.w, tc.ctx...); math.Abs(got-tc.want) > 1e-12 {
+				t.Fatalf("expected %g; got %g", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLanguageModelPrefersSeenNGrams(t *testing.T) {
+	for _, s := range []Smoothing{AddK, GoodTuring, WittenBell, KneserNey, ModifiedKneserNey} {
+		t.Run(s.String(), func(t *testing.T) {
+			m := newTestLanguageModel(s, 3)
+			seen := m.Prob("on", "dog", "sat")
+			unseen := m.Prob("red", "dog", "sat")
+			if seen <= unseen {
+				t.Fatalf("expected %g > %g", seen, unseen)
+			}
+		})
+	}
+}
+
+func TestLanguageModelAddKZero(t *testing.T) {
+	m := newTestLanguageModel(AddK, 3)
+	for _, k := range []float64{0, -1} {
+		m.K = k
+		for _, ctx := range [][]string{nil, {"the"}, {"unknown"}, {"x", "y"}} {
+			t.Run(fmt.Sprintf("%g %v", k, ctx), func(t *testing.T) {
+				var sum float64
+				for _, w := range m.Words() {
+					p := m.Prob(w, ctx...)
+					if math.IsNaN(p) || p < 0 || p > 1 {
+						t.Fatalf("invalid probability P(%s|%v) = %g", w, ctx, p)
+					}
+					sum += p
+				}
+				if math.Abs(sum-1) > 1e-9 {
+					t.Fatalf("expected 1; got %g", sum)
+				}
+			})
+		}
+	}
+}
+
+func TestLanguageModelTrigramsWithoutBigrams(t *testing.T) {
+	ts := strings.Fields(lmTestText)
+	m := NewLanguageModel(KneserNey, new(Unigrams).Add(ts...), nil, new(Trigrams).Add(ts...))
+	if got := m.Order(); got != 1 {
+		t.Fatalf("expected 1; got %d", got)
+	}
+}
+
+func TestLanguageModelKnown(t *testing.T) {
+	m := newTestLanguageModel(AddK, 1)
+	tests := []struct {
+		w    string
+		want bool
+	}{
+		{"cat", true},
+		{UNK, true},
+		{"unknown", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.w, func(t *testing.T) {
+			if got := m.Known(tc.w); got != tc.want {
+				t.Fatalf("expected %t; got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSmoothingString(t *testing.T) {
+	tests := []struct {
+		s    Smoothing
+		want string
+	}{
+		{AddK, "add-k"},
+		{ModifiedKneserNey, "modified-kneser-ney"},
+		{Smoothing(42), "unknown"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			if got := tc.s.String(); got != tc.want {
+				t.Fatalf("expected %s; got %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -123,22 +123,7 @@ func (m *CharTrigrams) unmarshal(bs []byte, f unmarshalFunc) error {
 // EachChar3Gram iterates of all character 3-grams in the given string.
 // It calls the supplied callback function for each such 3-gram.
 func EachChar3Gram(str string, f func(string)) {
-	pos := make([]int, 0, len(str)+1)
-	for i := range str {
-		pos = append(pos, i)
-		add3Gram(pos, str, f)
-	}
-	pos = append(pos, len(str))
-	add3Gram(pos, str, f)
-}
-
-func add3Gram(pos []int, str string, f func(string)) {
-	if len(pos) < 4 {
-		return
-	}
-	s := pos[len(pos)-4]
-	e := pos[len(pos)-1]
-	f(str[s:e])
+	EachCharNGram(str, 3, f)
 }
 
 // Unigrams represents the absolute unigram frequencies.
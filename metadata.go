@@ -0,0 +1,199 @@
+package corpus
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DTAMetadata represents the CMDI metadata of a DTA corpus file.
+type DTAMetadata struct {
+	Creator, CreationDate, SelfLink, Profile string
+	Title, Subtitle                          string
+	Authors                                  []DTAPerson
+	Date, Place, Publisher                   string // publication of the original
+	Genre, SubGenre                          string
+	Language                                 string
+	Classes                                  []DTAClass
+	Resources                                []DTAResource
+}
+
+// DTAPerson represents a person in the metadata.
+type DTAPerson struct {
+	Surname  string `xml:"surname"`
+	Forename string `xml:"forename"`
+}
+
+// String returns the name of the person in the form `Surname, Forename`.
+func (p DTAPerson) String() string {
+	if p.Forename == "" {
+		return p.Surname
+	}
+	return p.Surname + ", " + p.Forename
+}
+
+// DTAClass represents a classification code of a DTA corpus file.
+type DTAClass struct {
+	Scheme, Code string
+}
+
+// DTAResource represents a resource proxy of the metadata.
+type DTAResource struct {
+	ID, Type, MimeType, Ref string
+}
+
+// Year returns the publication year of the original.
+// It returns 0 if the year cannot be determined.
+func (m *DTAMetadata) Year() int {
+	if len(m.Date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(m.Date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// DTAReadMetadataAndClose is a convenience function that reads
+// the metadata of a DTA file and closes the reader.
+func DTAReadMetadataAndClose(r io.ReadCloser) (*DTAMetadata, error) {
+	var m *DTAMetadata
+	err := readAndClose(r, func(r io.Reader) error {
+		var err error
+		m, err = DTAReadMetadata(r)
+		return err
+	})
+	return m, err
+}
+
+// DTAReadMetadata reads the CMDI metadata header of a DTA corpus file.
+// It stops reading after the metadata header.
+func DTAReadMetadata(r io.Reader) (*DTAMetadata, error) {
+	return DTAReadMetadataContext(context.Background(), r)
+}
+
+// DTAReadMetadataContext reads the CMDI metadata header of a DTA
+// corpus file. It handles cancellation and errors in the same way
+// as DTAReadTokensContext.
+func DTAReadMetadataContext(ctx context.Context, r io.Reader) (*DTAMetadata, error) {
+	d := xml.NewDecoder(contextReader{ctx: ctx, r: r})
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, errors.New("invalid dta corpus file: missing metadata")
+		}
+		if err != nil {
+			return nil, wrapContext(ctx, err, "invalid dta corpus file")
+		}
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "TextCorpus" {
+			return nil, errors.New("invalid dta corpus file: missing metadata")
+		}
+		if start.Name.Local != "CMD" {
+			continue
+		}
+		var cmd cmdiCMD
+		if err := d.DecodeElement(&cmd, &start); err != nil {
+			return nil, wrapContext(ctx, err, "invalid dta corpus file")
+		}
+		return cmd.metadata(), nil
+	}
+}
+
+type cmdiCMD struct {
+	Header struct {
+		Creator      string `xml:"MdCreator"`
+		CreationDate string `xml:"MdCreationDate"`
+		SelfLink     string `xml:"MdSelfLink"`
+		Profile      string `xml:"MdProfile"`
+	} `xml:"Header"`
+	Resources []struct {
+		ID   string `xml:"id,attr"`
+		Type struct {
+			MimeType string `xml:"mimetype,attr"`
+			Text     string `xml:",chardata"`
+		} `xml:"ResourceType"`
+		Ref string `xml:"ResourceRef"`
+	} `xml:"Resources>ResourceProxyList>ResourceProxy"`
+	TEI struct {
+		Titles      []cmdiTyped `xml:"fileDesc>titleStmt>title"`
+		Authors     []DTAPerson `xml:"fileDesc>titleStmt>author>persName"`
+		Publication struct {
+			Place     string      `xml:"pubPlace"`
+			Dates     []cmdiTyped `xml:"date"`
+			Publisher string      `xml:"publisher>name"`
+		} `xml:"fileDesc>sourceDesc>biblFull>publicationStmt"`
+		Languages []struct {
+			Ident string `xml:"ident,attr"`
+		} `xml:"profileDesc>langUsage>language"`
+		Classes []struct {
+			Scheme string `xml:"scheme,attr"`
+			Text   string `xml:",chardata"`
+		} `xml:"profileDesc>textClass>classCode"`
+	} `xml:"Components>teiHeader"`
+}
+
+type cmdiTyped struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+func (cmd *cmdiCMD) metadata() *DTAMetadata {
+	m := &DTAMetadata{
+		Creator:      strings.TrimSpace(cmd.Header.Creator),
+		CreationDate: strings.TrimSpace(cmd.Header.CreationDate),
+		SelfLink:     strings.TrimSpace(cmd.Header.SelfLink),
+		Profile:      strings.TrimSpace(cmd.Header.Profile),
+		Place:        strings.TrimSpace(cmd.TEI.Publication.Place),
+		Publisher:    strings.TrimSpace(cmd.TEI.Publication.Publisher),
+	}
+	for _, r := range cmd.Resources {
+		m.Resources = append(m.Resources, DTAResource{
+			ID:       r.ID,
+			Type:     strings.TrimSpace(r.Type.Text),
+			MimeType: r.Type.MimeType,
+			Ref:      strings.TrimSpace(r.Ref),
+		})
+	}
+	for _, t := range cmd.TEI.Titles {
+		switch t.Type {
+		case "main":
+			m.Title = strings.TrimSpace(t.Text)
+		case "sub":
+			m.Subtitle = strings.TrimSpace(t.Text)
+		}
+	}
+	for _, a := range cmd.TEI.Authors {
+		m.Authors = append(m.Authors, DTAPerson{
+			Surname:  strings.TrimSpace(a.Surname),
+			Forename: strings.TrimSpace(a.Forename),
+		})
+	}
+	for _, d := range cmd.TEI.Publication.Dates {
+		if d.Type == "publication" {
+			m.Date = strings.TrimSpace(d.Text)
+		}
+	}
+	if len(cmd.TEI.Languages) > 0 {
+		m.Language = cmd.TEI.Languages[0].Ident
+	}
+	for _, c := range cmd.TEI.Classes {
+		class := DTAClass{Scheme: c.Scheme, Code: strings.TrimSpace(c.Text)}
+		switch {
+		case strings.HasSuffix(class.Scheme, "#dtamain"):
+			m.Genre = class.Code
+		case strings.HasSuffix(class.Scheme, "#dtasub"):
+			m.SubGenre = class.Code
+		}
+		m.Classes = append(m.Classes, class)
+	}
+	return m
+}
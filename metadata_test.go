@@ -0,0 +1,85 @@
+package corpus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDTAReadMetadata(t *testing.T) {
+	m, err := DTAReadMetadataAndClose(openDTATestFile(t))
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	tests := []struct {
+		name, got, want string
+	}{
+		{"creator", m.Creator, "Deutsches Textarchiv"},
+		{"creation date", m.CreationDate, "2017-09-01"},
+		{"self link", m.SelfLink, "http://www.deutschestextarchiv.de/api/cmdi/abel_leibmedicus_1699"},
+		{"profile", m.Profile, "clarin.eu:cr1:p_1381926654438"},
+		{"title", m.Title, "Wohlerfahrner Leib-Medicus Der Studenten"},
+		{"author", m.Authors[0].String(), "Abel, Heinrich Caspar"},
+		{"date", m.Date, "1699"},
+		{"place", m.Place, "Leipzig"},
+		{"publisher", m.Publisher, "Groschuff"},
+		{"genre", m.Genre, "Fachtext"},
+		{"sub genre", m.SubGenre, "Medizin"},
+		{"language", m.Language, "deu"},
+		{"resource type", m.Resources[1].MimeType, "application/xhtml+xml"},
+		{"resource ref", m.Resources[3].Ref, "http://www.deutschestextarchiv.de/abel_leibmedicus_1699"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("expected %q; got %q", tc.want, tc.got)
+			}
+		})
+	}
+	if got := m.Year(); got != 1699 {
+		t.Fatalf("expected 1699; got %d", got)
+	}
+	if got := len(m.Authors); got != 1 {
+		t.Fatalf("expected 1 author; got %d", got)
+	}
+	if got := len(m.Resources); got != 5 {
+		t.Fatalf("expected 5 resources; got %d", got)
+	}
+	if got := len(m.Classes); got != 7 {
+		t.Fatalf("expected 7 classes; got %d", got)
+	}
+}
+
+func TestDTAReadMetadataErrors(t *testing.T) {
+	tests := []struct{ test string }{
+		{`<D-Spin></D-Spin>`},
+		{`<D-Spin><TextCorpus/></D-Spin>`},
+		{`<D-Spin><MetaData><source><CMD>`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.test, func(t *testing.T) {
+			if _, err := DTAReadMetadata(strings.NewReader(tc.test)); err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+		})
+	}
+}
+
+func TestDTAMetadataYear(t *testing.T) {
+	tests := []struct {
+		date string
+		want int
+	}{
+		{"", 0},
+		{"16xx", 0},
+		{"1699", 1699},
+		{"2017-09-01T09:56:19Z", 2017},
+	}
+	for _, tc := range tests {
+		t.Run(tc.date, func(t *testing.T) {
+			m := DTAMetadata{Date: tc.date}
+			if got := m.Year(); got != tc.want {
+				t.Fatalf("expected %d; got %d", tc.want, got)
+			}
+		})
+	}
+}
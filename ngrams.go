@@ -0,0 +1,350 @@
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// NGrams is a map of token n-grams of arbitrary order. It is
+// implemented as a trie: the n-grams with the same first token share
+// the sub map of order n-1 that is returned by Prefix. The sub map of
+// order 0 holds the count of a complete n-gram.
+type NGrams struct {
+	order  int
+	total  uint64
+	ngrams map[string]*NGrams
+}
+
+// NewNGrams returns a new n-gram map of the given order. It panics
+// if the order is not positive.
+func NewNGrams(order int) *NGrams {
+	if order < 1 {
+		panic(invalidNGramsOrder(order))
+	}
+	return &NGrams{order: order}
+}
+
+func invalidNGramsOrder(order int) string {
+	return fmt.Sprintf("corpus: invalid n-gram order: %d", order)
+}
+
+// Order returns the order of the n-grams in the map.
+func (m *NGrams) Order() int {
+	if m == nil {
+		return 0
+	}
+	return m.order
+}
+
+// Add adds all n-grams of the given range of tokens to the map. It
+// panics if the order of the map is not positive (e.g. for the zero
+// value of NGrams).
+func (m *NGrams) Add(ts ...string) *NGrams {
+	if m.order < 1 {
+		panic(invalidNGramsOrder(m.order))
+	}
+	for i := 0; i+m.order <= len(ts); i++ {
+		m.add(ts[i:i+m.order], 1)
+	}
+	return m
+}
+
+func (m *NGrams) add(ts []string, n uint64) {
+	m.total += n
+	if len(ts) == 0 {
+		return
+	}
+	m.child(ts[0]).add(ts[1:], n)
+}
+
+func (m *NGrams) child(t string) *NGrams {
+	if m.ngrams == nil {
+		m.ngrams = make(map[string]*NGrams)
+	}
+	child, ok := m.ngrams[t]
+	if !ok {
+		child = &NGrams{order: m.order - 1}
+		m.ngrams[t] = child
+	}
+	return child
+}
+
+// Append appends all n-grams of another map to this map. A map
+// without order (e.g. the zero value of NGrams) adopts the order of
+// the other map. Append panics if the orders of the maps differ.
+func (m *NGrams) Append(o *NGrams) *NGrams {
+	if o == nil {
+		return m
+	}
+	if m.order == 0 && m.total == 0 {
+		m.order = o.order
+	}
+	if m.order != o.order {
+		panic(fmt.Sprintf("corpus: cannot append n-grams of order %d to n-grams of order %d",
+			o.order, m.order))
+	}
+	m.total += o.total
+	for k, v := range o.ngrams {
+		m.child(k).Append(v)
+	}
+	return m
+}
+
+// Get returns the number of n-grams that start with the given
+// tokens. If the number of tokens equals the order of the map,
+// the count of the according n-gram is returned.
+func (m *NGrams) Get(ts ...string) uint64 {
+	return m.Prefix(ts...).Total()
+}
+
+// Prefix returns the map of the (n-k)-grams that follow the given
+// k tokens. It returns nil if no n-gram starts with the tokens.
+func (m *NGrams) Prefix(ts ...string) *NGrams {
+	for _, t := range ts {
+		if m == nil {
+			return nil
+		}
+		m = m.ngrams[t]
+	}
+	return m
+}
+
+// Total returns the total number of n-grams in the map.
+func (m *NGrams) Total() uint64 {
+	if m == nil {
+		return 0
+	}
+	return m.total
+}
+
+// Len returns the number of different heads of the n-grams in the
+// map. For an order of 1 this is the number of different unigrams.
+func (m *NGrams) Len() uint64 {
+	if m == nil {
+		return 0
+	}
+	return uint64(len(m.ngrams))
+}
+
+// Each calls the supplied callback function for each n-gram in the
+// map and its count. The slice of tokens is only valid during the call.
+func (m *NGrams) Each(f func([]string, uint64)) {
+	if m == nil {
+		return
+	}
+	m.each(make([]string, 0, m.order), f)
+}
+
+func (m *NGrams) each(ts []string, f func([]string, uint64)) {
+	if m.order <= 0 {
+		f(ts, m.total)
+		return
+	}
+	for k, v := range m.ngrams {
+		v.each(append(ts, k), f)
+	}
+}
+
+// NGramsOfUnigrams converts the given unigrams to n-grams of order 1.
+func NGramsOfUnigrams(u *Unigrams) *NGrams {
+	m := NewNGrams(1)
+	u.Each(func(k string, v uint64) {
+		m.add([]string{k}, v)
+	})
+	return m
+}
+
+// NGramsOfBigrams converts the given bigrams to n-grams of order 2.
+func NGramsOfBigrams(b *Bigrams) *NGrams {
+	m := NewNGrams(2)
+	b.Each(func(k string, u *Unigrams) {
+		m.child(k).Append(NGramsOfUnigrams(u))
+		m.total += u.Total()
+	})
+	return m
+}
+
+// NGramsOfTrigrams converts the given trigrams to n-grams of order 3.
+func NGramsOfTrigrams(t *Trigrams) *NGrams {
+	m := NewNGrams(3)
+	t.Each(func(k string, b *Bigrams) {
+		m.child(k).Append(NGramsOfBigrams(b))
+		m.total += b.Total()
+	})
+	return m
+}
+
+// Unigrams converts n-grams of order 1 to unigrams.
+func (m *NGrams) Unigrams() (*Unigrams, error) {
+	if err := m.checkOrder(1); err != nil {
+		return nil, err
+	}
+	u := new(Unigrams)
+	for k, v := range m.ngrams {
+		if u.unigrams == nil {
+			u.unigrams = make(map[string]uint64)
+		}
+		u.unigrams[k] += v.total
+		u.total += v.total
+	}
+	return u, nil
+}
+
+// Bigrams converts n-grams of order 2 to bigrams.
+func (m *NGrams) Bigrams() (*Bigrams, error) {
+	if err := m.checkOrder(2); err != nil {
+		return nil, err
+	}
+	b := new(Bigrams)
+	for k, v := range m.ngrams {
+		u, _ := v.Unigrams()
+		b.AppendUnigrams(k, u)
+	}
+	return b, nil
+}
+
+// Trigrams converts n-grams of order 3 to trigrams.
+func (m *NGrams) Trigrams() (*Trigrams, error) {
+	if err := m.checkOrder(3); err != nil {
+		return nil, err
+	}
+	t := new(Trigrams)
+	for k, v := range m.ngrams {
+		b, _ := v.Bigrams()
+		t.AppendBigrams(k, b)
+	}
+	return t, nil
+}
+
+func (m *NGrams) checkOrder(order int) error {
+	if m.Order() != order {
+		return errors.Errorf("invalid order: expected %d; got %d", order, m.Order())
+	}
+	return nil
+}
+
+type jsonNGrams struct {
+	Order      int
+	Total, Len uint64
+	Counts     map[string]uint64      `json:",omitempty"` // order 1
+	NGrams     map[string]*jsonNGrams `json:",omitempty"` // order > 1
+}
+
+// jsonNGramsCompat is used to read n-grams as well as the
+// serializations of Unigrams, Bigrams and Trigrams.
+type jsonNGramsCompat struct {
+	Order      int
+	Total, Len uint64
+	Counts     map[string]uint64
+	NGrams     map[string]*jsonNGrams
+	Unigrams   map[string]uint64
+	Bigrams    map[string]*Unigrams
+	Trigrams   map[string]*Bigrams
+}
+
+// MarshalJSON implements JSON marshaling.
+func (m *NGrams) MarshalJSON() ([]byte, error) {
+	return m.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling. It also accepts the
+// JSON serializations of Unigrams, Bigrams and Trigrams. An error is
+// returned if the order of the n-grams is not positive.
+func (m *NGrams) UnmarshalJSON(bs []byte) error {
+	// Empty legacy maps are serialized as null, so the format is
+	// determined by the presence of the keys.
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(bs, &keys); err != nil {
+		return err
+	}
+	var legacy int
+	for order, key := range []string{"Unigrams", "Bigrams", "Trigrams"} {
+		if _, ok := keys[key]; ok {
+			legacy = order + 1
+			break
+		}
+	}
+	return m.unmarshal(bs, json.Unmarshal, legacy)
+}
+
+// GobEncode implement gob marhsaling.
+func (m *NGrams) GobEncode() ([]byte, error) {
+	return m.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling. It also accepts the gob
+// serializations of Unigrams, Bigrams and Trigrams. An error is
+// returned if the order of the n-grams is not positive. Gob does not
+// encode empty maps, so the gob serializations of empty Unigrams,
+// Bigrams and Trigrams cannot be told apart and result in an error.
+func (m *NGrams) GobDecode(bs []byte) error {
+	return m.unmarshal(bs, unmarshalGob, 0)
+}
+
+func (m *NGrams) marshal(f marshalFunc) ([]byte, error) {
+	return f(m.toJSON())
+}
+
+// unmarshal unmarshals n-grams. If legacy is not 0, the serialization
+// is one of Unigrams, Bigrams or Trigrams of the given order.
+func (m *NGrams) unmarshal(bs []byte, f unmarshalFunc, legacy int) error {
+	var tmp jsonNGramsCompat
+	if err := f(bs, &tmp); err != nil {
+		return err
+	}
+	switch {
+	case legacy == 1 || tmp.Unigrams != nil:
+		*m = *NGramsOfUnigrams(&Unigrams{unigrams: tmp.Unigrams, total: tmp.Total})
+	case legacy == 2 || tmp.Bigrams != nil:
+		*m = *NGramsOfBigrams(&Bigrams{bigrams: tmp.Bigrams, total: tmp.Total})
+	case legacy == 3 || tmp.Trigrams != nil:
+		*m = *NGramsOfTrigrams(&Trigrams{trigrams: tmp.Trigrams, total: tmp.Total})
+	default:
+		if tmp.Order < 1 {
+			return errors.Errorf("invalid n-gram order: %d", tmp.Order)
+		}
+		*m = *ngramsOfJSON(&jsonNGrams{
+			Order:  tmp.Order,
+			Total:  tmp.Total,
+			Counts: tmp.Counts,
+			NGrams: tmp.NGrams,
+		}, tmp.Order)
+	}
+	return nil
+}
+
+func (m *NGrams) toJSON() *jsonNGrams {
+	j := &jsonNGrams{Order: m.Order(), Total: m.Total(), Len: m.Len()}
+	for k, v := range m.ngrams {
+		if m.order == 1 {
+			if j.Counts == nil {
+				j.Counts = make(map[string]uint64)
+			}
+			j.Counts[k] = v.total
+			continue
+		}
+		if j.NGrams == nil {
+			j.NGrams = make(map[string]*jsonNGrams)
+		}
+		j.NGrams[k] = v.toJSON()
+	}
+	return j
+}
+
+func ngramsOfJSON(j *jsonNGrams, order int) *NGrams {
+	m := &NGrams{order: order}
+	if j == nil {
+		return m
+	}
+	m.total = j.Total
+	for k, v := range j.Counts {
+		m.child(k).total = v
+	}
+	for k, v := range j.NGrams {
+		m.child(k)
+		m.ngrams[k] = ngramsOfJSON(v, order-1)
+	}
+	return m
+}
@@ -0,0 +1,237 @@
+package corpus
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNGrams(t *testing.T) {
+	m := NewNGrams(4).Add("a", "b", "c", "d", "a", "b", "c", "d")
+	tests := []struct {
+		ngram string
+		count uint64
+	}{
+		{"", 5},
+		{"a", 2},
+		{"a b", 2},
+		{"a b c d", 2},
+		{"b c d a", 1},
+		{"d a b c", 1},
+		{"a b c x", 0},
+		{"x", 0},
+		{"a b c d a", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ngram, func(t *testing.T) {
+			if got := m.Get(strings.Fields(tc.ngram)...); got != tc.count {
+				t.Fatalf("expected %d; got %d", tc.count, got)
+			}
+		})
+	}
+	if got := m.Total(); got != 5 {
+		t.Fatalf("expected 5; got %d", got)
+	}
+	if got := m.Len(); got != 4 {
+		t.Fatalf("expected 4; got %d", got)
+	}
+	if got := m.Prefix("b", "c").Order(); got != 2 {
+		t.Fatalf("expected 2; got %d", got)
+	}
+	if got := m.Prefix("b", "x"); got != nil {
+		t.Fatalf("expected nil; got %v", got)
+	}
+}
+
+func TestNGramsAppend(t *testing.T) {
+	tests := []struct {
+		test, other *NGrams
+		ngram       []string
+		count       uint64
+		total       uint64
+	}{
+		{NewNGrams(2), nil, []string{"a", "b"}, 0, 0},
+		{NewNGrams(2), NewNGrams(2).Add("a", "b"), []string{"a", "b"}, 1, 1},
+		{NewNGrams(2).Add("a", "b"), NewNGrams(2).Add("a", "b", "c"), []string{"a", "b"}, 2, 3},
+		{new(NGrams), NewNGrams(2).Add("a", "b", "c"), []string{"a", "b"}, 1, 2},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%v", tc.other), func(t *testing.T) {
+			m := tc.test.Append(tc.other)
+			if got := m.Get(tc.ngram...); got != tc.count {
+				t.Fatalf("expected %d; got %d", tc.count, got)
+			}
+			if got := m.Total(); got != tc.total {
+				t.Fatalf("expected %d; got %d", tc.total, got)
+			}
+			if tc.other != nil && m.Order() != tc.other.Order() {
+				t.Fatalf("expected %d; got %d", tc.other.Order(), m.Order())
+			}
+		})
+	}
+}
+
+func TestNGramsAppendOrderMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected a panic; got nil")
+		}
+	}()
+	NewNGrams(3).Append(NewNGrams(2).Add("a", "b"))
+}
+
+func TestNGramsEach(t *testing.T) {
+	var got []string
+	NewNGrams(2).Add("a", "b", "a", "b").Each(func(ts []string, n uint64) {
+		got = append(got, fmt.Sprintf("%s:%d", strings.Join(ts, " "), n))
+	})
+	sort.Strings(got)
+	if want := []string{"a b:2", "b a:1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestNGramsConversion(t *testing.T) {
+	ts := []string{"a", "b", "c", "a", "b", "d"}
+	u := new(Unigrams).Add(ts...)
+	b := new(Bigrams).Add(ts...)
+	tr := new(Trigrams).Add(ts...)
+	if got, want := NGramsOfUnigrams(u), NewNGrams(1).Add(ts...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if got, want := NGramsOfBigrams(b), NewNGrams(2).Add(ts...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if got, want := NGramsOfTrigrams(tr), NewNGrams(3).Add(ts...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if got, err := NewNGrams(1).Add(ts...).Unigrams(); err != nil || !reflect.DeepEqual(got, u) {
+		t.Fatalf("expected %v; got %v (%v)", u, got, err)
+	}
+	if got, err := NewNGrams(2).Add(ts...).Bigrams(); err != nil || !reflect.DeepEqual(got, b) {
+		t.Fatalf("expected %v; got %v (%v)", b, got, err)
+	}
+	if got, err := NewNGrams(3).Add(ts...).Trigrams(); err != nil || !reflect.DeepEqual(got, tr) {
+		t.Fatalf("expected %v; got %v (%v)", tr, got, err)
+	}
+	if _, err := NewNGrams(2).Trigrams(); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
+
+func TestNGramsMarshal(t *testing.T) {
+	type codec struct {
+		name      string
+		marshal   func(interface{}) ([]byte, error)
+		unmarshal func([]byte, interface{}) error
+	}
+	codecs := []codec{
+		{"json", json.Marshal, json.Unmarshal},
+		{"gob", marshalGob, unmarshalGob},
+	}
+	ts := []string{"ab", "cd", "ef", "ab", "cd", "ef"}
+	tests := []struct {
+		name string
+		data interface{}
+		want *NGrams
+	}{
+		{"order 1", NewNGrams(1).Add(ts...), NewNGrams(1).Add(ts...)},
+		{"order 5", NewNGrams(5).Add(ts...), NewNGrams(5).Add(ts...)},
+		{"empty order 2", NewNGrams(2), NewNGrams(2)},
+		{"unigrams", new(Unigrams).Add(ts...), NewNGrams(1).Add(ts...)},
+		{"bigrams", new(Bigrams).Add(ts...), NewNGrams(2).Add(ts...)},
+		{"trigrams", new(Trigrams).Add(ts...), NewNGrams(3).Add(ts...)},
+	}
+	for _, c := range codecs {
+		for _, tc := range tests {
+			t.Run(c.name+" "+tc.name, func(t *testing.T) {
+				bs, err := c.marshal(tc.data)
+				if err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+				got := new(NGrams)
+				if err := c.unmarshal(bs, got); err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Fatalf("expected %v; got %v", tc.want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestNGramsUnmarshalEmptyLegacy(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want *NGrams
+	}{
+		{"unigrams", new(Unigrams), NewNGrams(1)},
+		{"bigrams", new(Bigrams), NewNGrams(2)},
+		{"trigrams", new(Trigrams), NewNGrams(3)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bs, err := json.Marshal(tc.data)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			got := new(NGrams)
+			if err := json.Unmarshal(bs, got); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNGramsInvalidOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"new 0", func() { NewNGrams(0) }},
+		{"new -1", func() { NewNGrams(-1) }},
+		{"zero value", func() { new(NGrams).Add("a", "b") }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("expected a panic; got nil")
+				}
+			}()
+			tc.f()
+		})
+	}
+	for _, str := range []string{`{}`, `{"Order":0,"Total":1}`, `{"Order":-1}`} {
+		t.Run(str, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(str), new(NGrams)); err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+		})
+	}
+}
+
+func TestNGramsGobEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	want := NewNGrams(3).Add("a", "b", "c", "d")
+	if err := gob.NewEncoder(buf).Encode(want); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	got := new(NGrams)
+	if err := gob.NewDecoder(buf).Decode(got); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
@@ -0,0 +1,30 @@
+package corpus
+
+// Box represents a rectangular bounding box on a page.
+// (X0, Y0) is the upper left and (X1, Y1) is the lower
+// right corner of the box.
+type Box struct {
+	X0, Y0, X1, Y1 int
+}
+
+// OCRToken represents a token of an OCR file with its position on the
+// page. The confidence of the token is normalized to the range [0, 1]
+// and is 0 if the OCR file does not contain any confidences. Tokens
+// that are split from the same word share the word's box, confidence
+// and alternatives.
+type OCRToken struct {
+	Token              Token
+	Box                Box
+	Conf               float64
+	Page, Region, Line string      // IDs of the page, region and line
+	Alternatives       []TextEquiv // all readings of the token's word
+}
+
+// TextEquiv represents one reading of an OCR word. In PAGE XML
+// ground truth files the reading with index 0 conventionally is
+// the ground truth and the reading with index 1 is the OCR result.
+type TextEquiv struct {
+	Index int
+	Text  string
+	Conf  float64
+}
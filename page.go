@@ -0,0 +1,220 @@
+package corpus
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PAGE reads tokens from PAGE XML files. The tokens are read from
+// the TextEquivs of the Word elements. Lines without Word elements
+// are read from the TextEquivs of the TextLine elements (the readings
+// of the whole line are then used as alternatives). The text is
+// split in the same way as DTAReadTokens splits tokens.
+//
+// Index selects the TextEquiv that is used for the tokens. By default
+// the TextEquiv with index 0 (the ground truth) is used. If there is
+// no TextEquiv with the given index, the first TextEquiv is used.
+// TextEquivs without an index attribute are numbered by their position.
+type PAGE struct {
+	Index int
+	r     io.Reader
+}
+
+// NewPAGE creates a new PAGE XML reader that reads from the given reader.
+func NewPAGE(r io.Reader) *PAGE {
+	return &PAGE{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (p *PAGE) Tokens(f func(Token)) error {
+	return p.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface.
+func (p *PAGE) TokensContext(ctx context.Context, f TokenFunc) error {
+	return p.OCRTokensContext(ctx, func(t OCRToken) error {
+		return f(t.Token)
+	})
+}
+
+// OCRTokens reads all tokens with their positions and alternatives.
+// The page ID of the tokens is the imageFilename of the page.
+func (p *PAGE) OCRTokens(f func(OCRToken)) error {
+	return p.OCRTokensContext(context.Background(), func(t OCRToken) error {
+		f(t)
+		return nil
+	})
+}
+
+// OCRTokensContext reads all tokens with their positions and
+// alternatives. It handles cancellation and errors in the same
+// way as DTAReadTokensContext.
+func (p *PAGE) OCRTokensContext(ctx context.Context, f func(OCRToken) error) error {
+	d := xml.NewDecoder(contextReader{ctx: ctx, r: p.r})
+	var page string
+	var regions []string
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapContext(ctx, err, "invalid page xml file")
+		}
+		switch tt := t.(type) {
+		case xml.StartElement:
+			switch tt.Name.Local {
+			case "Page":
+				page, regions = xmlAttr(tt, "imageFilename"), nil
+			case "TextRegion":
+				regions = append(regions, xmlAttr(tt, "id"))
+			case "TextLine":
+				var line pageTextLine
+				if err := d.DecodeElement(&line, &tt); err != nil {
+					return wrapContext(ctx, err, "invalid page xml file")
+				}
+				var region string
+				if len(regions) > 0 {
+					region = regions[len(regions)-1]
+				}
+				if err := p.line(ctx, page, region, line, f); err != nil {
+					return stopped(err)
+				}
+			}
+		case xml.EndElement:
+			if tt.Name.Local == "TextRegion" && len(regions) > 0 {
+				regions = regions[:len(regions)-1]
+			}
+		}
+	}
+}
+
+func (p *PAGE) line(ctx context.Context, page, region string, line pageTextLine, f func(OCRToken) error) error {
+	if len(line.Words) == 0 {
+		equivs := pageTextEquivs(line.TextEquivs)
+		equiv := selectTextEquiv(equivs, p.Index)
+		w := OCRToken{
+			Box:          line.Coords.box(),
+			Conf:         equiv.Conf,
+			Page:         page,
+			Region:       region,
+			Line:         line.ID,
+			Alternatives: equivs,
+		}
+		return tokenizeOCRWord(ctx, w, equiv.Text, f)
+	}
+	for _, word := range line.Words {
+		equivs := pageTextEquivs(word.TextEquivs)
+		equiv := selectTextEquiv(equivs, p.Index)
+		w := OCRToken{
+			Box:          word.Coords.box(),
+			Conf:         equiv.Conf,
+			Page:         page,
+			Region:       region,
+			Line:         line.ID,
+			Alternatives: equivs,
+		}
+		if err := tokenizeOCRWord(ctx, w, equiv.Text, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectTextEquiv returns the TextEquiv with the given index. If no
+// such TextEquiv exists, the first one (or an empty one) is returned.
+func selectTextEquiv(equivs []TextEquiv, index int) TextEquiv {
+	for _, e := range equivs {
+		if e.Index == index {
+			return e
+		}
+	}
+	if len(equivs) > 0 {
+		return equivs[0]
+	}
+	return TextEquiv{}
+}
+
+type pageTextLine struct {
+	ID         string          `xml:"id,attr"`
+	Coords     pageCoords      `xml:"Coords"`
+	Words      []pageWord      `xml:"Word"`
+	TextEquivs []pageTextEquiv `xml:"TextEquiv"`
+}
+
+type pageWord struct {
+	Coords     pageCoords      `xml:"Coords"`
+	TextEquivs []pageTextEquiv `xml:"TextEquiv"`
+}
+
+type pageTextEquiv struct {
+	Index   string  `xml:"index,attr"`
+	Conf    float64 `xml:"conf,attr"`
+	Unicode string  `xml:"Unicode"`
+}
+
+func pageTextEquivs(pes []pageTextEquiv) []TextEquiv {
+	var equivs []TextEquiv
+	for i, pe := range pes {
+		index, err := strconv.Atoi(pe.Index)
+		if err != nil {
+			index = i
+		}
+		equivs = append(equivs, TextEquiv{Index: index, Text: pe.Unicode, Conf: pe.Conf})
+	}
+	return equivs
+}
+
+type pageCoords struct {
+	Points string `xml:"points,attr"`
+	Point  []struct {
+		X int `xml:"x,attr"`
+		Y int `xml:"y,attr"`
+	} `xml:"Point"`
+}
+
+// box returns the bounding box of the coordinates.
+func (c pageCoords) box() Box {
+	var xs, ys []int
+	for _, p := range strings.Fields(c.Points) {
+		xy := strings.Split(p, ",")
+		if len(xy) != 2 {
+			continue
+		}
+		x, errx := strconv.Atoi(xy[0])
+		y, erry := strconv.Atoi(xy[1])
+		if errx != nil || erry != nil {
+			continue
+		}
+		xs, ys = append(xs, x), append(ys, y)
+	}
+	for _, p := range c.Point {
+		xs, ys = append(xs, p.X), append(ys, p.Y)
+	}
+	if len(xs) == 0 {
+		return Box{}
+	}
+	b := Box{X0: xs[0], Y0: ys[0], X1: xs[0], Y1: ys[0]}
+	for i := range xs {
+		b.X0, b.X1 = minInt(b.X0, xs[i]), maxInt(b.X1, xs[i])
+		b.Y0, b.Y1 = minInt(b.Y0, ys[i]), maxInt(b.Y1, ys[i])
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,81 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func readPAGETestFile(t *testing.T, index int) []OCRToken {
+	r := openTestFile(t, "testdata/page.xml")
+	defer r.Close()
+	p := NewPAGE(r)
+	p.Index = index
+	var ts []OCRToken
+	if err := p.OCRTokens(func(t OCRToken) { ts = append(ts, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return ts
+}
+
+func TestPAGETokens(t *testing.T) {
+	tests := []struct {
+		index int
+		want  []Token
+	}{
+		{0, []Token{"D", ".", "Henrici", "Caſparis", "Abelii", ","}},
+		{1, []Token{"D", ",", "Hcnrici", "Cafparis", "Abelii", ","}},
+		{2, []Token{"D", ".", "Hcnrici", "Caſparis", "Abelii", ","}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.index), func(t *testing.T) {
+			var got []Token
+			for _, t := range readPAGETestFile(t, tc.index) {
+				got = append(got, t.Token)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPAGEOCRTokens(t *testing.T) {
+	tests := []struct {
+		i    int
+		want OCRToken
+	}{
+		{1, OCRToken{Token: ".", Box: Box{100, 100, 160, 140}, Page: "0001.png",
+			Region: "r1", Line: "r1l1", Alternatives: []TextEquiv{
+				{Index: 0, Text: "D."}, {Index: 1, Text: "D,", Conf: .8}}}},
+		{2, OCRToken{Token: "Henrici", Box: Box{180, 100, 400, 140}, Conf: 1,
+			Page: "0001.png", Region: "r1", Line: "r1l1", Alternatives: []TextEquiv{
+				{Index: 1, Text: "Hcnrici", Conf: .7}, {Index: 0, Text: "Henrici", Conf: 1}}}},
+		{3, OCRToken{Token: "Caſparis", Box: Box{100, 160, 300, 200},
+			Page: "0001.png", Region: "r2", Line: "r2l1", Alternatives: []TextEquiv{
+				{Index: 0, Text: "Caſparis Abelii,"}, {Index: 1, Text: "Cafparis Abelii,"}}}},
+	}
+	ts := readPAGETestFile(t, 0)
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.i), func(t *testing.T) {
+			if got := ts[tc.i]; !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPAGEUnigrams(t *testing.T) {
+	r := openTestFile(t, "testdata/page.xml")
+	defer r.Close()
+	u := new(Unigrams)
+	if err := NewPAGE(r).Tokens(func(t Token) { u.Add(string(t)) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if got := u.Total(); got != 6 {
+		t.Fatalf("expected 6; got %d", got)
+	}
+	if got := u.Get("Henrici"); got != 1 {
+		t.Fatalf("expected 1; got %d", got)
+	}
+}
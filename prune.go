@@ -0,0 +1,242 @@
+package corpus
+
+import (
+	"math"
+	"sort"
+)
+
+// The Prune methods remove rare or uninformative n-grams from the
+// maps. The total counts and the lengths of the maps are updated accordingly.
+// If unk is not empty, the counts of the pruned entries are folded into
+// the entry unk of the according context (and the total counts stay
+// the same); the entry unk itself is never pruned. Contexts that lose
+// all of their entries are removed.
+
+// PruneMin removes all unigrams that occur less than min times.
+func (u *Unigrams) PruneMin(min uint64, unk string) *Unigrams {
+	return u.prune(unk, keepMin(min))
+}
+
+// PruneTopK keeps only the k most frequent unigrams.
+func (u *Unigrams) PruneTopK(k int, unk string) *Unigrams {
+	if u == nil {
+		return u
+	}
+	return u.prune(unk, keepTopK(u.unigrams, unk, k))
+}
+
+// PruneEntropy removes all unigrams whose weighted relative entropy
+// P(w)*log(P(w)/Q(w)) to the uniform distribution Q is less than
+// the given threshold.
+func (u *Unigrams) PruneEntropy(threshold float64, unk string) *Unigrams {
+	n, q := float64(u.Total()), 1/float64(u.Len())
+	return u.prune(unk, keepEntropy(threshold, func(_ string, c uint64) (float64, float64, float64) {
+		p := float64(c) / n
+		return p, p, q
+	}))
+}
+
+func (u *Unigrams) prune(unk string, keep func(string, uint64) bool) *Unigrams {
+	if u == nil {
+		return u
+	}
+	u.total -= pruneCounts(u.unigrams, unk, keep)
+	return u
+}
+
+// PruneMin removes all bigrams that occur less than min times.
+func (b *Bigrams) PruneMin(min uint64, unk string) *Bigrams {
+	return b.prune(unk, func(string, *Unigrams) func(string, uint64) bool {
+		return keepMin(min)
+	})
+}
+
+// PruneTopK keeps only the k most frequent bigrams for each first token.
+func (b *Bigrams) PruneTopK(k int, unk string) *Bigrams {
+	return b.prune(unk, func(_ string, u *Unigrams) func(string, uint64) bool {
+		return keepTopK(u.unigrams, unk, k)
+	})
+}
+
+// PruneEntropy removes all bigrams (v, w) whose contribution
+// P(v,w)*log(P(w|v)/P(w)) to the relative entropy of the bigram
+// and the unigram model is less than the given threshold. The
+// probabilities of the unigram model are taken from the given unigrams.
+// Bigrams with unknown unigrams are not pruned.
+func (b *Bigrams) PruneEntropy(threshold float64, lower *Unigrams, unk string) *Bigrams {
+	n := float64(b.Total())
+	return b.prune(unk, func(_ string, u *Unigrams) func(string, uint64) bool {
+		return keepEntropy(threshold, func(w string, c uint64) (float64, float64, float64) {
+			return float64(c) / n, float64(c) / float64(u.total),
+				float64(lower.Get(w)) / float64(lower.Total())
+		})
+	})
+}
+
+func (b *Bigrams) prune(unk string, keep func(string, *Unigrams) func(string, uint64) bool) *Bigrams {
+	if b == nil {
+		return b
+	}
+	for k, u := range b.bigrams {
+		total := u.total
+		u.prune(unk, keep(k, u))
+		b.total -= total - u.total
+		if u.Len() == 0 {
+			delete(b.bigrams, k)
+		}
+	}
+	return b
+}
+
+// PruneMin removes all trigrams that occur less than min times.
+func (t *Trigrams) PruneMin(min uint64, unk string) *Trigrams {
+	return t.prune(func(_ string, b *Bigrams) {
+		b.PruneMin(min, unk)
+	})
+}
+
+// PruneTopK keeps only the k most frequent trigrams for each context
+// of the first two tokens.
+func (t *Trigrams) PruneTopK(k int, unk string) *Trigrams {
+	return t.prune(func(_ string, b *Bigrams) {
+		b.PruneTopK(k, unk)
+	})
+}
+
+// PruneEntropy removes all trigrams (u, v, w) whose contribution
+// P(u,v,w)*log(P(w|u,v)/P(w|v)) to the relative entropy of the
+// trigram and the bigram model is less than the given threshold. The
+// probabilities of the bigram model are taken from the given bigrams.
+// Trigrams with unknown bigrams are not pruned.
+func (t *Trigrams) PruneEntropy(threshold float64, lower *Bigrams, unk string) *Trigrams {
+	n := float64(t.Total())
+	return t.prune(func(_ string, b *Bigrams) {
+		b.prune(unk, func(v string, u *Unigrams) func(string, uint64) bool {
+			l := lower.Get(v)
+			return keepEntropy(threshold, func(w string, c uint64) (float64, float64, float64) {
+				return float64(c) / n, float64(c) / float64(u.total),
+					float64(l.Get(w)) / float64(l.Total())
+			})
+		})
+	})
+}
+
+func (t *Trigrams) prune(f func(string, *Bigrams)) *Trigrams {
+	if t == nil {
+		return t
+	}
+	for k, b := range t.trigrams {
+		total := b.total
+		f(k, b)
+		t.total -= total - b.total
+		if b.Len() == 0 {
+			delete(t.trigrams, k)
+		}
+	}
+	return t
+}
+
+// PruneMin removes all 3-grams that occur less than min times.
+func (m *CharTrigrams) PruneMin(min uint64, unk string) *CharTrigrams {
+	return m.prune(unk, keepMin(min))
+}
+
+// PruneTopK keeps only the k most frequent 3-grams.
+func (m *CharTrigrams) PruneTopK(k int, unk string) *CharTrigrams {
+	if m == nil {
+		return m
+	}
+	return m.prune(unk, keepTopK(m.m, unk, k))
+}
+
+// PruneEntropy removes all 3-grams whose weighted relative entropy
+// P(g)*log(P(g)/Q(g)) to the uniform distribution Q is less than the
+// given threshold.
+func (m *CharTrigrams) PruneEntropy(threshold float64, unk string) *CharTrigrams {
+	n, q := float64(m.Total()), 1/float64(m.Len())
+	return m.prune(unk, keepEntropy(threshold, func(_ string, c uint64) (float64, float64, float64) {
+		p := float64(c) / n
+		return p, p, q
+	}))
+}
+
+func (m *CharTrigrams) prune(unk string, keep func(string, uint64) bool) *CharTrigrams {
+	if m == nil {
+		return m
+	}
+	m.n -= pruneCounts(m.m, unk, keep)
+	return m
+}
+
+// pruneCounts removes all entries for which keep returns false. If unk
+// is not empty, the pruned counts are added to the entry unk.
+// It returns the number of removed counts.
+func pruneCounts(m map[string]uint64, unk string, keep func(string, uint64) bool) uint64 {
+	var pruned uint64
+	for k, v := range m {
+		if k != unk && !keep(k, v) {
+			delete(m, k)
+			pruned += v
+		}
+	}
+	if unk != "" && pruned > 0 {
+		m[unk] += pruned
+		return 0
+	}
+	return pruned
+}
+
+func keepMin(min uint64) func(string, uint64) bool {
+	return func(_ string, c uint64) bool {
+		return c >= min
+	}
+}
+
+// keepTopK keeps the k most frequent entries of the map (not counting
+// unk). Ties are broken by the lexical order of the entries.
+func keepTopK(m map[string]uint64, unk string, k int) func(string, uint64) bool {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		if key != unk {
+			keys = append(keys, key)
+		}
+	}
+	sort.Sort(byCount{keys, m})
+	keep := make(map[string]bool, k)
+	for i := 0; i < k && i < len(keys); i++ {
+		keep[keys[i]] = true
+	}
+	return func(key string, _ uint64) bool {
+		return keep[key]
+	}
+}
+
+// byCount sorts keys by their descending counts and by their
+// lexical order.
+type byCount struct {
+	keys []string
+	m    map[string]uint64
+}
+
+func (s byCount) Len() int      { return len(s.keys) }
+func (s byCount) Swap(i, j int) { s.keys[i], s.keys[j] = s.keys[j], s.keys[i] }
+func (s byCount) Less(i, j int) bool {
+	if s.m[s.keys[i]] != s.m[s.keys[j]] {
+		return s.m[s.keys[i]] > s.m[s.keys[j]]
+	}
+	return s.keys[i] < s.keys[j]
+}
+
+// keepEntropy keeps the entries with p*log(c/q) >= threshold, where
+// p is the joint probability, c the conditional probability of the
+// entry and q the probability of the lower order model. Entries
+// without a lower order probability are kept.
+func keepEntropy(threshold float64, f func(string, uint64) (float64, float64, float64)) func(string, uint64) bool {
+	return func(w string, n uint64) bool {
+		p, c, q := f(w, n)
+		if q <= 0 || math.IsNaN(q) {
+			return true
+		}
+		return p*math.Log(c/q) >= threshold
+	}
+}
@@ -0,0 +1,167 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func unigramsMap(u *Unigrams) map[string]uint64 {
+	m := make(map[string]uint64)
+	u.Each(func(k string, v uint64) { m[k] = v })
+	return m
+}
+
+func TestUnigramsPrune(t *testing.T) {
+	ts := []string{"a", "a", "a", "b", "b", "c", "d"}
+	tests := []struct {
+		name  string
+		prune func(*Unigrams) *Unigrams
+		want  map[string]uint64
+	}{
+		{"min 2", func(u *Unigrams) *Unigrams { return u.PruneMin(2, "") },
+			map[string]uint64{"a": 3, "b": 2}},
+		{"min 2 unk", func(u *Unigrams) *Unigrams { return u.PruneMin(2, UNK) },
+			map[string]uint64{"a": 3, "b": 2, UNK: 2}},
+		{"min 4", func(u *Unigrams) *Unigrams { return u.PruneMin(4, "") },
+			map[string]uint64{}},
+		{"top 1", func(u *Unigrams) *Unigrams { return u.PruneTopK(1, "") },
+			map[string]uint64{"a": 3}},
+		{"top 3", func(u *Unigrams) *Unigrams { return u.PruneTopK(3, UNK) },
+			map[string]uint64{"a": 3, "b": 2, "c": 1, UNK: 1}},
+		{"top 10", func(u *Unigrams) *Unigrams { return u.PruneTopK(10, "") },
+			map[string]uint64{"a": 3, "b": 2, "c": 1, "d": 1}},
+		// p*log(p*4) >= 0 for p >= 1/4
+		{"entropy", func(u *Unigrams) *Unigrams { return u.PruneEntropy(0, "") },
+			map[string]uint64{"a": 3, "b": 2}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u := tc.prune(new(Unigrams).Add(ts...))
+			checkUnigrams(t, u)
+			if got := unigramsMap(u); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+			if got := u.Len(); got != uint64(len(tc.want)) {
+				t.Fatalf("expected %d; got %d", len(tc.want), got)
+			}
+		})
+	}
+	if got := (*Unigrams)(nil).PruneMin(1, ""); got != nil {
+		t.Fatalf("expected nil; got %v", got)
+	}
+}
+
+func TestUnigramsPruneKeepsUNK(t *testing.T) {
+	u := new(Unigrams).Add("a", "a", UNK).PruneMin(2, UNK)
+	if got, want := unigramsMap(u), map[string]uint64{"a": 2, UNK: 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestBigramsPrune(t *testing.T) {
+	// ab:2 ba:2 ac:1 cb:1 bc:1
+	ts := []string{"a", "b", "a", "b", "a", "c", "b", "c"}
+	tests := []struct {
+		name        string
+		prune       func(*Bigrams) *Bigrams
+		total, len  uint64
+		first, next string
+		count       uint64
+	}{
+		{"min 2", func(b *Bigrams) *Bigrams { return b.PruneMin(2, "") }, 4, 2, "b", "a", 2},
+		{"min 2 unk", func(b *Bigrams) *Bigrams { return b.PruneMin(2, UNK) }, 7, 3, "b", UNK, 1},
+		{"min 3", func(b *Bigrams) *Bigrams { return b.PruneMin(3, "") }, 0, 0, "a", "b", 0},
+		{"top 1", func(b *Bigrams) *Bigrams { return b.PruneTopK(1, "") }, 5, 3, "a", "c", 0},
+		{"top 1 unk", func(b *Bigrams) *Bigrams { return b.PruneTopK(1, UNK) }, 7, 3, "a", UNK, 1},
+		{"entropy", func(b *Bigrams) *Bigrams {
+			// prunes a c and b c: 1/7*log((1/3)/(2/8)) < 0.05
+			return b.PruneEntropy(.05, new(Unigrams).Add(ts...), "")
+		}, 5, 3, "a", "c", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.prune(new(Bigrams).Add(ts...))
+			checkBigrams(t, b)
+			if got := b.Total(); got != tc.total {
+				t.Fatalf("expected %d; got %d", tc.total, got)
+			}
+			if got := b.Len(); got != tc.len {
+				t.Fatalf("expected %d; got %d", tc.len, got)
+			}
+			if got := b.Get(tc.first).Get(tc.next); got != tc.count {
+				t.Fatalf("expected %d; got %d", tc.count, got)
+			}
+		})
+	}
+}
+
+func TestTrigramsPrune(t *testing.T) {
+	// abc:2 bca:2 cab:2 abd:1 bdx:1
+	ts := []string{"a", "b", "c", "a", "b", "c", "a", "b", "d", "x"}
+	tests := []struct {
+		name       string
+		prune      func(*Trigrams) *Trigrams
+		total, len uint64
+		ngram      [3]string
+		count      uint64
+	}{
+		{"min 2", func(tr *Trigrams) *Trigrams { return tr.PruneMin(2, "") },
+			6, 3, [3]string{"a", "b", "c"}, 2},
+		{"min 2 unk", func(tr *Trigrams) *Trigrams { return tr.PruneMin(2, UNK) },
+			8, 3, [3]string{"a", "b", UNK}, 1},
+		{"top 1", func(tr *Trigrams) *Trigrams { return tr.PruneTopK(1, "") },
+			7, 3, [3]string{"a", "b", "d"}, 0},
+		{"entropy", func(tr *Trigrams) *Trigrams {
+			return tr.PruneEntropy(0, new(Bigrams).Add(ts...), "")
+		}, 8, 3, [3]string{"a", "b", "d"}, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := tc.prune(new(Trigrams).Add(ts...))
+			checkTrigrams(t, tr)
+			if got := tr.Total(); got != tc.total {
+				t.Fatalf("expected %d; got %d", tc.total, got)
+			}
+			if got := tr.Len(); got != tc.len {
+				t.Fatalf("expected %d; got %d", tc.len, got)
+			}
+			if got := tr.Get(tc.ngram[0]).Get(tc.ngram[1]).Get(tc.ngram[2]); got != tc.count {
+				t.Fatalf("expected %d; got %d", tc.count, got)
+			}
+		})
+	}
+}
+
+func TestCharTrigramsPrune(t *testing.T) {
+	tests := []struct {
+		name       string
+		prune      func(*CharTrigrams) *CharTrigrams
+		total, len uint64
+	}{
+		{"min 2", func(m *CharTrigrams) *CharTrigrams { return m.PruneMin(2, "") }, 5, 2},
+		{"min 3 unk", func(m *CharTrigrams) *CharTrigrams { return m.PruneMin(3, "###") }, 6, 2},
+		{"top 1", func(m *CharTrigrams) *CharTrigrams { return m.PruneTopK(1, "") }, 3, 1},
+		{"entropy", func(m *CharTrigrams) *CharTrigrams { return m.PruneEntropy(0, "") }, 5, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// aba:3 bab:2 abc:1
+			m := tc.prune(new(CharTrigrams).Add("abababa").Add("abc"))
+			var total uint64
+			m.Each(func(_ string, c uint64) { total += c })
+			if total != m.Total() {
+				t.Fatalf("inconsistent map: expected %d; got %d", total, m.Total())
+			}
+			if got := m.Total(); got != tc.total {
+				t.Fatalf("expected %d; got %d", tc.total, got)
+			}
+			if got := m.Len(); got != tc.len {
+				t.Fatalf("expected %d; got %d", tc.len, got)
+			}
+		})
+	}
+	if got := fmt.Sprint((*CharTrigrams)(nil).PruneTopK(1, "")); got != "<nil>" {
+		t.Fatalf("expected <nil>; got %s", got)
+	}
+}
@@ -0,0 +1,392 @@
+package corpus
+
+import "sync"
+
+// syncShards is the number of shards of the concurrency-safe maps.
+const syncShards = 32
+
+// shard returns the shard index of the given key (FNV-1a).
+func shard(key string) int {
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return int(h % syncShards)
+}
+
+// SyncUnigrams is a concurrency-safe version of Unigrams. The map is
+// split into shards with their own read-write locks, so concurrent
+// readers and writers of different keys do not block each other. The
+// zero value is ready to use. A SyncUnigrams must not be copied.
+type SyncUnigrams struct {
+	shards [syncShards]struct {
+		sync.RWMutex
+		m Unigrams
+	}
+}
+
+// Add adds a range of unigrams to the map.
+func (u *SyncUnigrams) Add(us ...string) *SyncUnigrams {
+	for _, unigram := range us {
+		s := &u.shards[shard(unigram)]
+		s.Lock()
+		s.m.Add(unigram)
+		s.Unlock()
+	}
+	return u
+}
+
+// AddUnigrams adds all unigrams to the map.
+func (u *SyncUnigrams) AddUnigrams(o *Unigrams) *SyncUnigrams {
+	o.Each(func(k string, v uint64) {
+		s := &u.shards[shard(k)]
+		s.Lock()
+		if s.m.unigrams == nil {
+			s.m.unigrams = make(map[string]uint64)
+		}
+		s.m.unigrams[k] += v
+		s.m.total += v
+		s.Unlock()
+	})
+	return u
+}
+
+// Total returns the total number of unigrams in the map.
+func (u *SyncUnigrams) Total() uint64 {
+	var total uint64
+	for i := range u.shards {
+		u.shards[i].RLock()
+		total += u.shards[i].m.Total()
+		u.shards[i].RUnlock()
+	}
+	return total
+}
+
+// Len returns the total number different unigrams in the map.
+func (u *SyncUnigrams) Len() uint64 {
+	var n uint64
+	for i := range u.shards {
+		u.shards[i].RLock()
+		n += u.shards[i].m.Len()
+		u.shards[i].RUnlock()
+	}
+	return n
+}
+
+// Get returns the count for the given unigram.
+func (u *SyncUnigrams) Get(unigram string) uint64 {
+	s := &u.shards[shard(unigram)]
+	s.RLock()
+	defer s.RUnlock()
+	return s.m.Get(unigram)
+}
+
+// Each calls the supplied callback function for each entry in the
+// map. The entries of each shard are copied before the callback
+// function is called, so it is safe to modify the map in the callback.
+func (u *SyncUnigrams) Each(f func(string, uint64)) {
+	for i := range u.shards {
+		u.shards[i].RLock()
+		tmp := new(Unigrams).AddUnigrams(&u.shards[i].m)
+		u.shards[i].RUnlock()
+		tmp.Each(f)
+	}
+}
+
+// Unigrams returns a copy of the map as Unigrams.
+func (u *SyncUnigrams) Unigrams() *Unigrams {
+	res := new(Unigrams)
+	for i := range u.shards {
+		u.shards[i].RLock()
+		res.AddUnigrams(&u.shards[i].m)
+		u.shards[i].RUnlock()
+	}
+	return res
+}
+
+// SyncBigrams is a concurrency-safe version of Bigrams. The bigrams
+// are sharded by their first token. The zero value is ready to use.
+// A SyncBigrams must not be copied.
+type SyncBigrams struct {
+	shards [syncShards]struct {
+		sync.RWMutex
+		m Bigrams
+	}
+}
+
+// Add adds a range of bigrams to the map.
+func (b *SyncBigrams) Add(bs ...string) *SyncBigrams {
+	for i := 1; i < len(bs); i++ {
+		s := &b.shards[shard(bs[i-1])]
+		s.Lock()
+		s.m.Add(bs[i-1], bs[i])
+		s.Unlock()
+	}
+	return b
+}
+
+// Append appends all the given bigrams to the map.
+func (b *SyncBigrams) Append(o *Bigrams) *SyncBigrams {
+	o.Each(func(k string, u *Unigrams) {
+		s := &b.shards[shard(k)]
+		s.Lock()
+		s.m.AppendUnigrams(k, u)
+		s.Unlock()
+	})
+	return b
+}
+
+// Total returns the total number of bigrams in the map.
+func (b *SyncBigrams) Total() uint64 {
+	var total uint64
+	for i := range b.shards {
+		b.shards[i].RLock()
+		total += b.shards[i].m.Total()
+		b.shards[i].RUnlock()
+	}
+	return total
+}
+
+// Len returns the total number of different unigrams in the map.
+func (b *SyncBigrams) Len() uint64 {
+	var n uint64
+	for i := range b.shards {
+		b.shards[i].RLock()
+		n += b.shards[i].m.Len()
+		b.shards[i].RUnlock()
+	}
+	return n
+}
+
+// Get returns a copy of the unigrams for the given head of a bigram.
+// The copy costs time and memory proportional to the number of
+// different second tokens; use Count to look up single bigrams.
+func (b *SyncBigrams) Get(first string) *Unigrams {
+	s := &b.shards[shard(first)]
+	s.RLock()
+	defer s.RUnlock()
+	u := s.m.Get(first)
+	if u == nil {
+		return nil
+	}
+	return new(Unigrams).AddUnigrams(u)
+}
+
+// Count returns the count of the given bigram.
+func (b *SyncBigrams) Count(first, second string) uint64 {
+	s := &b.shards[shard(first)]
+	s.RLock()
+	defer s.RUnlock()
+	return s.m.Get(first).Get(second)
+}
+
+// Each calls the supplied callback function for each entry in the
+// map. The callback function is called with copies of the entries.
+func (b *SyncBigrams) Each(f func(string, *Unigrams)) {
+	for i := range b.shards {
+		b.shards[i].RLock()
+		tmp := new(Bigrams).Append(&b.shards[i].m)
+		b.shards[i].RUnlock()
+		tmp.Each(f)
+	}
+}
+
+// Bigrams returns a copy of the map as Bigrams.
+func (b *SyncBigrams) Bigrams() *Bigrams {
+	res := new(Bigrams)
+	for i := range b.shards {
+		b.shards[i].RLock()
+		res.Append(&b.shards[i].m)
+		b.shards[i].RUnlock()
+	}
+	return res
+}
+
+// SyncTrigrams is a concurrency-safe version of Trigrams. The
+// trigrams are sharded by their first token. The zero value is ready
+// to use. A SyncTrigrams must not be copied.
+type SyncTrigrams struct {
+	shards [syncShards]struct {
+		sync.RWMutex
+		m Trigrams
+	}
+}
+
+// Add adds a range of trigrams to the map.
+func (t *SyncTrigrams) Add(ts ...string) *SyncTrigrams {
+	for i := 2; i < len(ts); i++ {
+		s := &t.shards[shard(ts[i-2])]
+		s.Lock()
+		s.m.Add(ts[i-2], ts[i-1], ts[i])
+		s.Unlock()
+	}
+	return t
+}
+
+// Append appends the given trigrams to the map.
+func (t *SyncTrigrams) Append(o *Trigrams) *SyncTrigrams {
+	o.Each(func(k string, b *Bigrams) {
+		s := &t.shards[shard(k)]
+		s.Lock()
+		s.m.AppendBigrams(k, b)
+		s.Unlock()
+	})
+	return t
+}
+
+// Total returns the total number of trigrams in the map.
+func (t *SyncTrigrams) Total() uint64 {
+	var total uint64
+	for i := range t.shards {
+		t.shards[i].RLock()
+		total += t.shards[i].m.Total()
+		t.shards[i].RUnlock()
+	}
+	return total
+}
+
+// Len returns the total number of different bigrams in the map.
+func (t *SyncTrigrams) Len() uint64 {
+	var n uint64
+	for i := range t.shards {
+		t.shards[i].RLock()
+		n += t.shards[i].m.Len()
+		t.shards[i].RUnlock()
+	}
+	return n
+}
+
+// Get returns a copy of the bigrams for the given head of a trigram.
+// The copy costs time and memory proportional to the number of
+// trigrams with the given head; use Count to look up single trigrams.
+func (t *SyncTrigrams) Get(first string) *Bigrams {
+	s := &t.shards[shard(first)]
+	s.RLock()
+	defer s.RUnlock()
+	b := s.m.Get(first)
+	if b == nil {
+		return nil
+	}
+	return new(Bigrams).Append(b)
+}
+
+// Count returns the count of the given trigram.
+func (t *SyncTrigrams) Count(first, second, third string) uint64 {
+	s := &t.shards[shard(first)]
+	s.RLock()
+	defer s.RUnlock()
+	return s.m.Get(first).Get(second).Get(third)
+}
+
+// Each calls the supplied callback function for each entry in the
+// map. The callback function is called with copies of the entries.
+func (t *SyncTrigrams) Each(f func(string, *Bigrams)) {
+	for i := range t.shards {
+		t.shards[i].RLock()
+		tmp := new(Trigrams).Append(&t.shards[i].m)
+		t.shards[i].RUnlock()
+		tmp.Each(f)
+	}
+}
+
+// Trigrams returns a copy of the map as Trigrams.
+func (t *SyncTrigrams) Trigrams() *Trigrams {
+	res := new(Trigrams)
+	for i := range t.shards {
+		t.shards[i].RLock()
+		res.Append(&t.shards[i].m)
+		t.shards[i].RUnlock()
+	}
+	return res
+}
+
+// SyncCharTrigrams is a concurrency-safe version of CharTrigrams.
+// The zero value is ready to use. A SyncCharTrigrams must not be
+// copied.
+type SyncCharTrigrams struct {
+	shards [syncShards]struct {
+		sync.RWMutex
+		m CharTrigrams
+	}
+}
+
+// Add adds all character 3-grams of the
+// supplied string into the map.
+func (m *SyncCharTrigrams) Add(str string) *SyncCharTrigrams {
+	EachChar3Gram(str, func(str string) {
+		m.add(str, 1)
+	})
+	return m
+}
+
+// Append appends the 3-grams of another map to this.
+func (m *SyncCharTrigrams) Append(o *CharTrigrams) *SyncCharTrigrams {
+	o.Each(func(str string, n uint64) {
+		m.add(str, n)
+	})
+	return m
+}
+
+func (m *SyncCharTrigrams) add(str string, n uint64) {
+	s := &m.shards[shard(str)]
+	s.Lock()
+	if s.m.m == nil {
+		s.m.m = make(map[string]uint64)
+	}
+	s.m.m[str] += n
+	s.m.n += n
+	s.Unlock()
+}
+
+// Get returns the number of the supplied 3-gram.
+func (m *SyncCharTrigrams) Get(str string) uint64 {
+	s := &m.shards[shard(str)]
+	s.RLock()
+	defer s.RUnlock()
+	return s.m.Get(str)
+}
+
+// Total returns the total number of 3-grams in the map.
+func (m *SyncCharTrigrams) Total() uint64 {
+	var total uint64
+	for i := range m.shards {
+		m.shards[i].RLock()
+		total += m.shards[i].m.Total()
+		m.shards[i].RUnlock()
+	}
+	return total
+}
+
+// Len return the number of different 3-grams in the map.
+func (m *SyncCharTrigrams) Len() uint64 {
+	var n uint64
+	for i := range m.shards {
+		m.shards[i].RLock()
+		n += m.shards[i].m.Len()
+		m.shards[i].RUnlock()
+	}
+	return n
+}
+
+// Each iterates over all character 3-grams in this map. The entries
+// of each shard are copied before the callback function is called.
+func (m *SyncCharTrigrams) Each(f func(string, uint64)) {
+	for i := range m.shards {
+		m.shards[i].RLock()
+		tmp := new(CharTrigrams).Append(&m.shards[i].m)
+		m.shards[i].RUnlock()
+		tmp.Each(f)
+	}
+}
+
+// CharTrigrams returns a copy of the map as CharTrigrams.
+func (m *SyncCharTrigrams) CharTrigrams() *CharTrigrams {
+	res := new(CharTrigrams)
+	for i := range m.shards {
+		m.shards[i].RLock()
+		res.Append(&m.shards[i].m)
+		m.shards[i].RUnlock()
+	}
+	return res
+}
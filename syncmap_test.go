@@ -0,0 +1,243 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var syncTestSentences = []string{
+	"a b c a b",
+	"b c d e a b c",
+	"Größe und Bäume und Größe",
+	"a",
+	"",
+}
+
+func TestSyncMaps(t *testing.T) {
+	var (
+		u  Unigrams
+		b  Bigrams
+		tr Trigrams
+		c  CharTrigrams
+	)
+	var (
+		su SyncUnigrams
+		sb SyncBigrams
+		st SyncTrigrams
+		sc SyncCharTrigrams
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, s := range syncTestSentences {
+			ts := strings.Fields(s)
+			u.Add(ts...)
+			b.Add(ts...)
+			tr.Add(ts...)
+			c.Add(s)
+			wg.Add(1)
+			go func(s string, ts []string) {
+				defer wg.Done()
+				su.Add(ts...)
+				sb.Add(ts...)
+				st.Add(ts...)
+				sc.Add(s)
+				// Concurrent reads.
+				su.Get("a")
+				sb.Get("a").Get("b")
+				st.Get("a").Get("b").Get("c")
+				sb.Count("a", "b")
+				st.Count("a", "b", "c")
+				sc.Total()
+			}(s, ts)
+		}
+	}
+	wg.Wait()
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"unigrams", su.Unigrams(), &u},
+		{"bigrams", sb.Bigrams(), &b},
+		{"trigrams", st.Trigrams(), &tr},
+		{"char trigrams", sc.CharTrigrams(), &c},
+		{"unigrams total", su.Total(), u.Total()},
+		{"bigrams total", sb.Total(), b.Total()},
+		{"trigrams total", st.Total(), tr.Total()},
+		{"char trigrams total", sc.Total(), c.Total()},
+		{"unigrams len", su.Len(), u.Len()},
+		{"bigrams len", sb.Len(), b.Len()},
+		{"trigrams len", st.Len(), tr.Len()},
+		{"char trigrams len", sc.Len(), c.Len()},
+		{"unigram", su.Get("und"), u.Get("und")},
+		{"bigram", sb.Get("a"), b.Get("a")},
+		{"trigram", st.Get("b"), tr.Get("b")},
+		{"bigram count", sb.Count("a", "b"), b.Get("a").Get("b")},
+		{"trigram count", st.Count("b", "c", "d"), tr.Get("b").Get("c").Get("d")},
+		{"unknown bigram count", sb.Count("x", "y"), uint64(0)},
+		{"unknown trigram count", st.Count("a", "x", "y"), uint64(0)},
+		{"char trigram", sc.Get("röß"), c.Get("röß")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, tc.got)
+			}
+		})
+	}
+}
+
+func TestSyncMapsAppend(t *testing.T) {
+	var c Counts
+	c.Add("a", "b", "c", "a", "b")
+	var (
+		su SyncUnigrams
+		sb SyncBigrams
+		st SyncTrigrams
+		sc SyncCharTrigrams
+	)
+	su.AddUnigrams(&c.Unigrams)
+	sb.Append(&c.Bigrams)
+	st.Append(&c.Trigrams)
+	sc.Append(&c.CharTrigrams)
+	if got := su.Unigrams(); !reflect.DeepEqual(got, &c.Unigrams) {
+		t.Fatalf("expected %v; got %v", c.Unigrams, got)
+	}
+	if got := sb.Bigrams(); !reflect.DeepEqual(got, &c.Bigrams) {
+		t.Fatalf("expected %v; got %v", c.Bigrams, got)
+	}
+	if got := st.Trigrams(); !reflect.DeepEqual(got, &c.Trigrams) {
+		t.Fatalf("expected %v; got %v", c.Trigrams, got)
+	}
+	if got := sc.CharTrigrams(); !reflect.DeepEqual(got, &c.CharTrigrams) {
+		t.Fatalf("expected %v; got %v", c.CharTrigrams, got)
+	}
+}
+
+func TestSyncMapsCopies(t *testing.T) {
+	var sb SyncBigrams
+	sb.Add("a", "b")
+	u := sb.Get("a")
+	u.Add("c")
+	if got := sb.Get("a").Get("c"); got != 0 {
+		t.Fatalf("expected 0; got %d", got)
+	}
+	if got := sb.Get("x"); got != nil {
+		t.Fatalf("expected nil; got %v", got)
+	}
+	var st SyncTrigrams
+	if got := st.Get("x"); got != nil {
+		t.Fatalf("expected nil; got %v", got)
+	}
+}
+
+func TestSyncMapsEach(t *testing.T) {
+	var su SyncUnigrams
+	su.Add("a", "b", "a")
+	got := make(map[string]uint64)
+	su.Each(func(k string, v uint64) {
+		got[k] = v
+		su.Add(k) // modifications in the callback must not dead lock
+	})
+	if want := map[string]uint64{"a": 2, "b": 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	var sb SyncBigrams
+	sb.Add("a", "b", "a")
+	n := 0
+	sb.Each(func(string, *Unigrams) { n++ })
+	if n != 2 {
+		t.Fatalf("expected 2; got %d", n)
+	}
+	var st SyncTrigrams
+	st.Add("a", "b", "a", "b")
+	n = 0
+	st.Each(func(string, *Bigrams) { n++ })
+	if n != 2 {
+		t.Fatalf("expected 2; got %d", n)
+	}
+	var sc SyncCharTrigrams
+	sc.Add("abab")
+	n = 0
+	sc.Each(func(string, uint64) { n++ })
+	if n != 2 {
+		t.Fatalf("expected 2; got %d", n)
+	}
+}
+
+// mutexUnigrams is a Unigrams map guarded by a single lock. It is
+// used as baseline in the benchmarks.
+type mutexUnigrams struct {
+	sync.RWMutex
+	m Unigrams
+}
+
+func benchmarkKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("token%d", i)
+	}
+	return keys
+}
+
+// benchmarkContention runs parallel lookups and updates with the given
+// percentage of writes.
+func benchmarkContention(b *testing.B, writes int, get func(string), add func(string)) {
+	keys := benchmarkKeys(1024)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%100 < writes {
+				add(keys[i%len(keys)])
+			} else {
+				get(keys[i%len(keys)])
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkUnigramsMutex(b *testing.B) {
+	for _, writes := range []int{0, 10, 50, 100} {
+		b.Run(fmt.Sprintf("writes=%d%%", writes), func(b *testing.B) {
+			var m mutexUnigrams
+			benchmarkContention(b, writes, func(k string) {
+				m.RLock()
+				m.m.Get(k)
+				m.RUnlock()
+			}, func(k string) {
+				m.Lock()
+				m.m.Add(k)
+				m.Unlock()
+			})
+		})
+	}
+}
+
+func BenchmarkSyncUnigrams(b *testing.B) {
+	for _, writes := range []int{0, 10, 50, 100} {
+		b.Run(fmt.Sprintf("writes=%d%%", writes), func(b *testing.B) {
+			var m SyncUnigrams
+			benchmarkContention(b, writes, func(k string) {
+				m.Get(k)
+			}, func(k string) {
+				m.Add(k)
+			})
+		})
+	}
+}
+
+func BenchmarkSyncBigrams(b *testing.B) {
+	for _, writes := range []int{0, 10, 50, 100} {
+		b.Run(fmt.Sprintf("writes=%d%%", writes), func(b *testing.B) {
+			var m SyncBigrams
+			benchmarkContention(b, writes, func(k string) {
+				m.Get(k)
+			}, func(k string) {
+				m.Add(k, k)
+			})
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package corpus
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AnnotatedToken represents a token of a DTA corpus file
+// together with the annotations of the different TCF layers.
+type AnnotatedToken struct {
+	Token      Token
+	ID         string // ID of the token
+	Sentence   string // ID of the sentence the token belongs to
+	Lemma      string
+	POS        string // STTS part of speech tag
+	Correction string // orthographic correction of the token
+	UPOS       string // universal part of speech tag
+	Features   string // morphological features
+	Misc       string // any other annotation
+}
+
+// Normalized returns the orthographic correction of the token.
+// If the token has no correction, the original token is returned.
+func (t AnnotatedToken) Normalized() Token {
+	if t.Correction == "" {
+		return t.Token
+	}
+	return Token(t.Correction)
+}
+
+// Sentence represents a sentence of annotated tokens.
+type Sentence struct {
+	ID       string
+	Comments []string
+	Tokens   []AnnotatedToken
+}
+
+// SentencesOf groups consecutive annotated tokens with
+// the same sentence ID into sentences.
+func SentencesOf(ts []AnnotatedToken) []Sentence {
+	var ss []Sentence
+	for _, t := range ts {
+		if len(ss) == 0 || ss[len(ss)-1].ID != t.Sentence {
+			ss = append(ss, Sentence{ID: t.Sentence})
+		}
+		ss[len(ss)-1].Tokens = append(ss[len(ss)-1].Tokens, t)
+	}
+	return ss
+}
+
+// DTAReadAnnotatedTokensAndClose is a convenience function that
+// reads all annotated tokens in a DTA file and closes the reader.
+func DTAReadAnnotatedTokensAndClose(r io.ReadCloser, f func(AnnotatedToken)) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadAnnotatedTokens(r, f)
+	})
+}
+
+// DTAReadAnnotatedTokens reads all tokens of a DTA corpus file
+// and resolves the annotations of the sentences, lemmas, POStags
+// and orthography layers. The tokens are reported in the order
+// of the tokens layer. The tokens are not split.
+func DTAReadAnnotatedTokens(r io.Reader, f func(AnnotatedToken)) error {
+	return DTAReadAnnotatedTokensContext(context.Background(), r,
+		func(t AnnotatedToken) error {
+			f(t)
+			return nil
+		})
+}
+
+// DTAReadAnnotatedTokensAndCloseContext is a convenience function that
+// reads all annotated tokens in a DTA file and closes the reader.
+func DTAReadAnnotatedTokensAndCloseContext(ctx context.Context, r io.ReadCloser, f func(AnnotatedToken) error) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadAnnotatedTokensContext(ctx, r, f)
+	})
+}
+
+// DTAReadAnnotatedTokensContext reads all annotated tokens of a DTA
+// corpus file. It handles cancellation and errors in the same way
+// as DTAReadTokensContext.
+//
+// The annotation layers follow the tokens layer in TCF files, so the
+// file is not streamed: the layers are read into memory before the
+// first token is reported.
+func DTAReadAnnotatedTokensContext(ctx context.Context, r io.Reader, f func(AnnotatedToken) error) error {
+	return dtaReadAnnotatedTokens(ctx, r, tcfLayers, f)
+}
+
+func dtaReadAnnotatedTokens(ctx context.Context, r io.Reader, layers []string, f func(AnnotatedToken) error) error {
+	tc, err := readTCFTextCorpus(ctx, r, layers)
+	if err != nil {
+		return err
+	}
+	ts, err := tc.annotatedTokens()
+	if err != nil {
+		return err
+	}
+	for _, t := range ts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f(t); err != nil {
+			return stopped(err)
+		}
+	}
+	return nil
+}
+
+// DTAReadSentencesAndClose is a convenience function that reads
+// all sentences in a DTA file and closes the reader.
+func DTAReadSentencesAndClose(r io.ReadCloser, f func([]Token)) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadSentences(r, f)
+	})
+}
+
+// DTAReadSentences reads all sentences from a DTA corpus file.
+// The tokens of each sentence are split in the same way as
+// DTAReadTokens splits them. Consecutive tokens that do not belong
+// to any sentence are reported as one sentence.
+func DTAReadSentences(r io.Reader, f func([]Token)) error {
+	return DTAReadSentencesContext(context.Background(), r,
+		func(s []Token) error {
+			f(s)
+			return nil
+		})
+}
+
+// DTAReadSentencesAndCloseContext is a convenience function that reads
+// all sentences in a DTA file and closes the reader.
+func DTAReadSentencesAndCloseContext(ctx context.Context, r io.ReadCloser, f func([]Token) error) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadSentencesContext(ctx, r, f)
+	})
+}
+
+// DTAReadSentencesContext reads all sentences from a DTA corpus file.
+// It handles cancellation and errors in the same way as
+// DTAReadTokensContext. Like DTAReadAnnotatedTokensContext it does
+// not stream the file: the tokens and sentences layers are read into
+// memory (the other layers are skipped) before the first sentence is
+// reported.
+func DTAReadSentencesContext(ctx context.Context, r io.Reader, f func([]Token) error) error {
+	var s []Token
+	var id string
+	var ferr error
+	layers := []string{"tokens", "sentences"}
+	err := dtaReadAnnotatedTokens(ctx, r, layers, func(t AnnotatedToken) error {
+		if len(s) > 0 && t.Sentence != id {
+			if ferr = f(s); ferr != nil {
+				return ferr
+			}
+			s = nil
+		}
+		id = t.Sentence
+		return tokenize(string(t.Token), func(t Token) error {
+			s = append(s, t)
+			return nil
+		})
+	})
+	if err != nil || ferr != nil {
+		return err
+	}
+	if len(s) > 0 {
+		return stopped(f(s))
+	}
+	return nil
+}
+
+// tcfLayers lists the names of the layers that are read.
+var tcfLayers = []string{"tokens", "sentences", "lemmas", "POStags", "orthography"}
+
+type tcfTextCorpus struct {
+	Lang      string
+	Tokens    []tcfAnnotation
+	Sentences []tcfAnnotation
+	Lemmas    []tcfAnnotation
+	POSTags   []tcfAnnotation
+	Ortho     []tcfAnnotation
+}
+
+// layer returns the annotations of the layer with the given name or
+// nil if the layer is unknown.
+func (tc *tcfTextCorpus) layer(name string) *[]tcfAnnotation {
+	switch name {
+	case "tokens":
+		return &tc.Tokens
+	case "sentences":
+		return &tc.Sentences
+	case "lemmas":
+		return &tc.Lemmas
+	case "POStags":
+		return &tc.POSTags
+	case "orthography":
+		return &tc.Ortho
+	}
+	return nil
+}
+
+// tcfLayer is used to decode the annotations of one layer.
+type tcfLayer struct {
+	Annotations []tcfAnnotation `xml:",any"`
+}
+
+type tcfAnnotation struct {
+	ID        string `xml:"ID,attr,omitempty"`
+	TokenIDs  string `xml:"tokenIDs,attr,omitempty"`
+	Operation string `xml:"operation,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+// readTCFTextCorpus reads the given layers of the TextCorpus element.
+// All other layers are skipped.
+func readTCFTextCorpus(ctx context.Context, r io.Reader, layers []string) (*tcfTextCorpus, error) {
+	d := xml.NewDecoder(contextReader{ctx: ctx, r: r})
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, errors.New("invalid dta corpus file: missing TextCorpus")
+		}
+		if err != nil {
+			return nil, wrapContext(ctx, err, "invalid dta corpus file")
+		}
+		if start, ok := t.(xml.StartElement); ok && start.Name.Local == "TextCorpus" {
+			tc := &tcfTextCorpus{Lang: xmlAttr(start, "lang")}
+			if err := tc.readLayers(d, layers); err != nil {
+				return nil, wrapContext(ctx, err, "invalid dta corpus file")
+			}
+			return tc, nil
+		}
+	}
+}
+
+// readLayers reads the layers up to the end of the TextCorpus element.
+func (tc *tcfTextCorpus) readLayers(d *xml.Decoder, layers []string) error {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		switch tt := t.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			as := tc.layer(tt.Name.Local)
+			if as == nil || !containsString(layers, tt.Name.Local) {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			var l tcfLayer
+			if err := d.DecodeElement(&l, &tt); err != nil {
+				return err
+			}
+			*as = l.Annotations
+		}
+	}
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+func (tc *tcfTextCorpus) annotatedTokens() ([]AnnotatedToken, error) {
+	ts := make([]AnnotatedToken, len(tc.Tokens))
+	ids := make(map[string]int, len(tc.Tokens))
+	for i, t := range tc.Tokens {
+		ts[i] = AnnotatedToken{Token: Token(t.Text), ID: t.ID}
+		ids[t.ID] = i
+	}
+	layers := []struct {
+		as  []tcfAnnotation
+		set func(*AnnotatedToken, tcfAnnotation)
+	}{
+		{tc.Sentences, func(t *AnnotatedToken, a tcfAnnotation) { t.Sentence = a.ID }},
+		{tc.Lemmas, func(t *AnnotatedToken, a tcfAnnotation) { t.Lemma = a.Text }},
+		{tc.POSTags, func(t *AnnotatedToken, a tcfAnnotation) { t.POS = a.Text }},
+		{tc.Ortho, func(t *AnnotatedToken, a tcfAnnotation) { t.Correction = a.Text }},
+	}
+	for _, l := range layers {
+		for _, a := range l.as {
+			for _, id := range strings.Fields(a.TokenIDs) {
+				i, ok := ids[id]
+				if !ok {
+					return nil, errors.Errorf(
+						"invalid dta corpus file: invalid token ID: %s", id)
+				}
+				l.set(&ts[i], a)
+			}
+		}
+	}
+	return ts, nil
+}
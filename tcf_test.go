@@ -0,0 +1,115 @@
+package corpus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDTAReadAnnotatedTokens(t *testing.T) {
+	tests := []struct {
+		i    int
+		want AnnotatedToken
+	}{
+		{0, AnnotatedToken{Token: "D.", ID: "w1", Sentence: "s1", Lemma: "D.", POS: "NE"}},
+		{2, AnnotatedToken{Token: "Caſparis", ID: "w3", Sentence: "s1",
+			Lemma: "Casparis", POS: "NE", Correction: "Casparis"}},
+		{9, AnnotatedToken{Token: ",", ID: "wa", Sentence: "s1", Lemma: ",", POS: "$,"}},
+		{18, AnnotatedToken{Token: "Univerſita\u0364ten", ID: "w13"}},
+	}
+	var ts []AnnotatedToken
+	err := DTAReadAnnotatedTokensAndClose(openDTATestFile(t), func(t AnnotatedToken) {
+		ts = append(ts, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(ts) != 19 {
+		t.Fatalf("expected 19 tokens; got %d", len(ts))
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.i), func(t *testing.T) {
+			if got := ts[tc.i]; got != tc.want {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDTAReadAnnotatedTokensErrors(t *testing.T) {
+	tests := []struct{ test string }{
+		{`<D-Spin></D-Spin>`},
+		{`<TextCorpus><tokens><token ID="w1">a</token></tokens>`},
+		{`<TextCorpus><tokens><token ID="w1">a</token></tokens>` +
+			`<lemmas><lemma tokenIDs="w2">a</lemma></lemmas></TextCorpus>`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.test, func(t *testing.T) {
+			err := DTAReadAnnotatedTokens(strings.NewReader(tc.test), func(AnnotatedToken) {})
+			if err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+		})
+	}
+}
+
+func TestDTAReadAnnotatedTokensCloseError(t *testing.T) {
+	err := DTAReadAnnotatedTokensAndClose(closeError(t), func(AnnotatedToken) {})
+	if errors.Cause(err) != errClose {
+		t.Fatalf("expected %s; got %v", errClose, err)
+	}
+}
+
+func TestDTAReadSentences(t *testing.T) {
+	tests := []struct {
+		i           int
+		len         int
+		first, last Token
+	}{
+		{0, 18, "D", "auf"},
+		{1, 4, "Schulen", "Univerſitaͤten"},
+	}
+	var ss [][]Token
+	err := DTAReadSentencesAndClose(openDTATestFile(t), func(s []Token) {
+		ss = append(ss, s)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if len(ss) != 2 {
+		t.Fatalf("expected 2 sentences; got %d", len(ss))
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.i), func(t *testing.T) {
+			s := ss[tc.i]
+			if got := len(s); got != tc.len {
+				t.Fatalf("expected %d; got %d", tc.len, got)
+			}
+			if got := s[0]; got != tc.first {
+				t.Fatalf("expected %q; got %q", tc.first, got)
+			}
+			if got := s[len(s)-1]; got != tc.last {
+				t.Fatalf("expected %q; got %q", tc.last, got)
+			}
+		})
+	}
+}
+
+func TestDTAReadSentencesSkipsLayers(t *testing.T) {
+	// The invalid lemmas layer is not read.
+	str := `<TextCorpus><tokens><token ID="w1">a</token><token ID="w2">b</token></tokens>` +
+		`<sentences><sentence ID="s1" tokenIDs="w1"/><sentence ID="s2" tokenIDs="w2"/></sentences>` +
+		`<lemmas><lemma tokenIDs="w3">c</lemma></lemmas></TextCorpus>`
+	var ss [][]Token
+	err := DTAReadSentences(strings.NewReader(str), func(s []Token) {
+		ss = append(ss, s)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if got := fmt.Sprint(ss); got != "[[a] [b]]" {
+		t.Fatalf("expected [[a] [b]]; got %s", got)
+	}
+}
@@ -0,0 +1,100 @@
+package corpus
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DTAWriteAnnotatedTokens writes the given annotated tokens as
+// D-Spin TCF file. It writes the tokens, sentences, lemmas, POStags
+// and orthography layers. Empty layers are omitted. The tokens and
+// sentences are assigned new consecutive IDs (w1, w2, ... and s1, s2,
+// ...). Tokens with the same sentence ID are written to the same sentence.
+func DTAWriteAnnotatedTokens(w io.Writer, lang string, ts []AnnotatedToken) error {
+	dspin := tcfDSpin{
+		Version:    "0.4",
+		TextCorpus: newTCFTextCorpusOut(lang, ts),
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.Wrapf(err, "cannot write dta corpus file")
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(dspin); err != nil {
+		return errors.Wrapf(err, "cannot write dta corpus file")
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return errors.Wrapf(err, "cannot write dta corpus file")
+	}
+	return nil
+}
+
+type tcfDSpin struct {
+	XMLName    xml.Name `xml:"http://www.dspin.de/data D-Spin"`
+	Version    string   `xml:"version,attr"`
+	MetaData   tcfMetaData
+	TextCorpus tcfTextCorpusOut
+}
+
+type tcfMetaData struct {
+	XMLName xml.Name `xml:"http://www.dspin.de/data/metadata MetaData"`
+}
+
+type tcfTextCorpusOut struct {
+	XMLName   xml.Name        `xml:"http://www.dspin.de/data/textcorpus TextCorpus"`
+	Lang      string          `xml:"lang,attr"`
+	Tokens    []tcfAnnotation `xml:"tokens>token"`
+	Sentences []tcfAnnotation `xml:"sentences>sentence"`
+	Lemmas    []tcfAnnotation `xml:"lemmas>lemma"`
+	POSTags   *tcfPOSTags     `xml:"POStags"`
+	Ortho     []tcfAnnotation `xml:"orthography>correction"`
+}
+
+type tcfPOSTags struct {
+	Tagset string          `xml:"tagset,attr"`
+	Tags   []tcfAnnotation `xml:"tag"`
+}
+
+func newTCFTextCorpusOut(lang string, ts []AnnotatedToken) tcfTextCorpusOut {
+	tc := tcfTextCorpusOut{Lang: lang}
+	var sids []string
+	sentences := make(map[string][]string)
+	var tags []tcfAnnotation
+	for i, t := range ts {
+		id := fmt.Sprintf("w%x", i+1)
+		tc.Tokens = append(tc.Tokens, tcfAnnotation{ID: id, Text: string(t.Token)})
+		if t.Sentence != "" {
+			if _, ok := sentences[t.Sentence]; !ok {
+				sids = append(sids, t.Sentence)
+			}
+			sentences[t.Sentence] = append(sentences[t.Sentence], id)
+		}
+		if t.Lemma != "" {
+			tc.Lemmas = append(tc.Lemmas, tcfAnnotation{TokenIDs: id, Text: t.Lemma})
+		}
+		if t.POS != "" {
+			tags = append(tags, tcfAnnotation{TokenIDs: id, Text: t.POS})
+		}
+		if t.Correction != "" {
+			tc.Ortho = append(tc.Ortho, tcfAnnotation{
+				TokenIDs:  id,
+				Operation: "replace",
+				Text:      t.Correction,
+			})
+		}
+	}
+	for i, sid := range sids {
+		tc.Sentences = append(tc.Sentences, tcfAnnotation{
+			ID:       fmt.Sprintf("s%x", i+1),
+			TokenIDs: strings.Join(sentences[sid], " "),
+		})
+	}
+	if len(tags) > 0 {
+		tc.POSTags = &tcfPOSTags{Tagset: "stts", Tags: tags}
+	}
+	return tc
+}
@@ -0,0 +1,75 @@
+package corpus
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDTAWriteAnnotatedTokensRoundTrip(t *testing.T) {
+	var want []AnnotatedToken
+	err := DTAReadAnnotatedTokensAndClose(openDTATestFile(t), func(t AnnotatedToken) {
+		want = append(want, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := DTAWriteAnnotatedTokens(buf, "de", want); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var got []AnnotatedToken
+	err = DTAReadAnnotatedTokens(bytes.NewReader(buf.Bytes()), func(t AnnotatedToken) {
+		got = append(got, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if !strings.Contains(buf.String(), `<POStags tagset="stts">`) {
+		t.Fatalf("missing POStags layer: %s", buf.String())
+	}
+}
+
+func TestDTAWriteAnnotatedTokensIDs(t *testing.T) {
+	ts := []AnnotatedToken{
+		{Token: "a", ID: "x", Sentence: "first"},
+		{Token: "b", ID: "y", Sentence: "second"},
+		{Token: "c", ID: "z", Sentence: "first"},
+		{Token: "d", ID: "z"},
+	}
+	want := []AnnotatedToken{
+		{Token: "a", ID: "w1", Sentence: "s1"},
+		{Token: "b", ID: "w2", Sentence: "s2"},
+		{Token: "c", ID: "w3", Sentence: "s1"},
+		{Token: "d", ID: "w4"},
+	}
+	buf := &bytes.Buffer{}
+	if err := DTAWriteAnnotatedTokens(buf, "de", ts); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	var got []AnnotatedToken
+	err := DTAReadAnnotatedTokens(buf, func(t AnnotatedToken) {
+		got = append(got, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestDTAWriteAnnotatedTokensWriteError(t *testing.T) {
+	ts := []AnnotatedToken{{Token: "a"}}
+	if err := DTAWriteAnnotatedTokens(errWriter{}, "de", ts); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errClose }
@@ -0,0 +1,198 @@
+package corpus
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TEIToken represents a token of a TEI-P5 file with its position.
+// Page is the n-attribute of the last page break and Line is the
+// number of the line on the page (starting at 1).
+type TEIToken struct {
+	Token Token
+	Page  string
+	Line  int
+}
+
+// TEI reads tokens from DTA TEI-P5 (base format) files.
+// The text is tokenized using the same rules as DTAReadTokens.
+// Words that are hyphenated at line breaks are joined.
+// The teiHeader and forme work (<fw>) are skipped.
+// By default the original forms of <choice> elements
+// (<orig>, <sic> and <abbr>) are used. If Normalized is set,
+// the regularized forms (<reg>, <corr> and <expan>) are used.
+type TEI struct {
+	Normalized bool
+	r          io.Reader
+}
+
+// NewTEI creates a new TEI reader that reads from the given reader.
+func NewTEI(r io.Reader) *TEI {
+	return &TEI{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (tei *TEI) Tokens(f func(Token)) error {
+	return tei.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface.
+func (tei *TEI) TokensContext(ctx context.Context, f TokenFunc) error {
+	return tei.TEITokensContext(ctx, func(t TEIToken) error {
+		return f(t.Token)
+	})
+}
+
+// TEITokens reads all tokens with their positions.
+func (tei *TEI) TEITokens(f func(TEIToken)) error {
+	return tei.TEITokensContext(context.Background(), func(t TEIToken) error {
+		f(t)
+		return nil
+	})
+}
+
+// TEITokensContext reads all tokens with their positions. It handles
+// cancellation and errors in the same way as DTAReadTokensContext.
+func (tei *TEI) TEITokensContext(ctx context.Context, f func(TEIToken) error) error {
+	d := xml.NewDecoder(contextReader{ctx: ctx, r: tei.r})
+	s := teiState{line: 1, f: func(t TEIToken) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return f(t)
+	}}
+	for s.err == nil {
+		t, err := d.Token()
+		if err == io.EOF {
+			s.endWord()
+			return stopped(s.err)
+		}
+		if err != nil {
+			return wrapContext(ctx, err, "invalid tei file")
+		}
+		switch tt := t.(type) {
+		case xml.CharData:
+			s.chars(string(tt))
+		case xml.StartElement:
+			if tei.skip(tt.Name.Local, s.choice > 0) {
+				if err := d.Skip(); err != nil {
+					return wrapContext(ctx, err, "invalid tei file")
+				}
+				continue
+			}
+			s.start(tt)
+		case xml.EndElement:
+			s.end(tt.Name.Local)
+		}
+	}
+	return stopped(s.err)
+}
+
+func (tei *TEI) skip(name string, inChoice bool) bool {
+	switch name {
+	case "teiHeader", "fw":
+		return true
+	case "orig", "sic", "abbr":
+		return inChoice && tei.Normalized
+	case "reg", "corr", "expan":
+		return inChoice && !tei.Normalized
+	}
+	return false
+}
+
+// teiBlocks lists the elements that separate words.
+var teiBlocks = map[string]bool{
+	"argument": true, "byline": true, "cell": true, "closer": true,
+	"dateline": true, "div": true, "docImprint": true, "docTitle": true,
+	"epigraph": true, "figure": true, "head": true, "item": true,
+	"l": true, "lg": true, "list": true, "note": true, "opener": true,
+	"p": true, "row": true, "salute": true, "signed": true, "sp": true,
+	"speaker": true, "stage": true, "table": true, "titlePage": true,
+	"titlePart": true, "trailer": true,
+}
+
+// teiHyphens lists the hyphenation marks at the end of lines.
+const teiHyphens = "-¬⸗"
+
+type teiState struct {
+	f       func(TEIToken) error
+	err     error
+	word    bytes.Buffer
+	page    string
+	line    int
+	wpage   string
+	wline   int
+	choice  int
+	joining bool
+}
+
+func (s *teiState) chars(str string) {
+	for _, r := range str {
+		if unicode.IsSpace(r) {
+			if !s.joining {
+				s.endWord()
+			}
+			continue
+		}
+		if s.word.Len() == 0 {
+			s.wpage, s.wline = s.page, s.line
+		}
+		s.joining = false
+		s.word.WriteRune(r)
+	}
+}
+
+func (s *teiState) start(e xml.StartElement) {
+	switch e.Name.Local {
+	case "choice":
+		s.choice++
+	case "lb":
+		s.lineBreak()
+		s.line++
+	case "pb":
+		if !s.joining {
+			s.endWord()
+		}
+		s.page = xmlAttr(e, "n")
+		s.line = 1
+	default:
+		if teiBlocks[e.Name.Local] {
+			s.endWord()
+		}
+	}
+}
+
+func (s *teiState) end(name string) {
+	switch {
+	case name == "choice":
+		s.choice--
+	case teiBlocks[name]:
+		s.endWord()
+	}
+}
+
+func (s *teiState) lineBreak() {
+	r, n := utf8.DecodeLastRune(s.word.Bytes())
+	if n == s.word.Len() || !strings.ContainsRune(teiHyphens, r) {
+		s.endWord()
+		return
+	}
+	s.word.Truncate(s.word.Len() - n)
+	s.joining = true
+}
+
+func (s *teiState) endWord() {
+	s.joining = false
+	if s.word.Len() == 0 || s.err != nil {
+		return
+	}
+	s.err = tokenize(s.word.String(), func(t Token) error {
+		return s.f(TEIToken{Token: t, Page: s.wpage, Line: s.wline})
+	})
+	s.word.Reset()
+}
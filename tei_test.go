@@ -0,0 +1,94 @@
+package corpus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readTEITestFile(t *testing.T, normalized bool) []TEIToken {
+	r := openTEITestFile(t)
+	defer r.Close()
+	tei := NewTEI(r)
+	tei.Normalized = normalized
+	var ts []TEIToken
+	if err := tei.TEITokens(func(t TEIToken) { ts = append(ts, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	return ts
+}
+
+func TestTEITokens(t *testing.T) {
+	tests := []struct {
+		normalized bool
+		want       []string
+	}{
+		{false, []string{
+			"D", ".", "Henrici", "Caſparis", "Abelii", ",",
+			"Das", "erſte", "Capitel", ".",
+			"Die", "Geſundheit", "iſt", "ein", "ſchoͤnes", "Gut", ",",
+			"welches", "vnd", "man", "ſehr",
+			"bewahren", "ſoll", ".", "Die", "Kranckheit", "aber",
+		}},
+		{true, []string{
+			"D", ".", "Henrici", "Caſparis", "Abelii", ",",
+			"Das", "erſte", "Capitel", ".",
+			"Die", "Geſundheit", "iſt", "ein", "schönes", "Gut", ",",
+			"welches", "und", "man", "ſehr",
+			"bewahren", "ſoll", ".", "Die", "Kranckheit", "aber",
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%t", tc.normalized), func(t *testing.T) {
+			var got []string
+			for _, t := range readTEITestFile(t, tc.normalized) {
+				got = append(got, string(t.Token))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTEITokenPositions(t *testing.T) {
+	tests := []struct {
+		i    int
+		want TEIToken
+	}{
+		{0, TEIToken{"D", "", 1}},
+		{6, TEIToken{"Das", "1", 2}},
+		{11, TEIToken{"Geſundheit", "1", 3}},
+		{12, TEIToken{"iſt", "1", 4}},
+		{25, TEIToken{"Kranckheit", "1", 6}},
+		{26, TEIToken{"aber", "2", 1}},
+	}
+	ts := readTEITestFile(t, false)
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d", tc.i), func(t *testing.T) {
+			if got := ts[tc.i]; got != tc.want {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTEITokener(t *testing.T) {
+	var tokener Tokener = NewTEI(strings.NewReader(
+		`<TEI><text><body><p>a-<lb/>b, c</p><p>d</p></body></text></TEI>`))
+	var got []Token
+	if err := tokener.Tokens(func(t Token) { got = append(got, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := []Token{"ab", ",", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestTEIError(t *testing.T) {
+	err := NewTEI(strings.NewReader(`<TEI><text>`)).Tokens(func(Token) {})
+	if err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
@@ -0,0 +1,83 @@
+package corpus
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+)
+
+// maxLineLength is the maximal length of a line in a plain text file.
+const maxLineLength = 1 << 24
+
+// Text reads tokens from plain UTF-8 text files. Each file is
+// considered to be one document. The text is split at white space
+// and the words are split in the same way as DTAReadTokens splits them.
+type Text struct {
+	r io.Reader
+}
+
+// NewText creates a new plain text reader that reads from the given reader.
+func NewText(r io.Reader) *Text {
+	return &Text{r: r}
+}
+
+// Tokens implements the Tokener interface.
+func (t *Text) Tokens(f func(Token)) error {
+	return t.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface. It handles
+// cancellation and errors in the same way as DTAReadTokensContext.
+func (t *Text) TokensContext(ctx context.Context, f TokenFunc) error {
+	i := NewTextTokenIterator(contextReader{ctx: ctx, r: t.r})
+	f = withContext(ctx, f)
+	for i.Next() {
+		if err := f(i.Token()); err != nil {
+			return stopped(err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return i.Err()
+}
+
+// Sentences reads the text line by line. Each non empty line is
+// considered to be one sentence.
+func (t *Text) Sentences(f func([]Token)) error {
+	return t.SentencesContext(context.Background(), func(s []Token) error {
+		f(s)
+		return nil
+	})
+}
+
+// SentencesContext reads the text line by line. Each non empty line
+// is considered to be one sentence. It handles cancellation and errors
+// in the same way as DTAReadTokensContext.
+func (t *Text) SentencesContext(ctx context.Context, f func([]Token) error) error {
+	s := bufio.NewScanner(contextReader{ctx: ctx, r: t.r})
+	s.Buffer(nil, maxLineLength)
+	for s.Scan() {
+		var sentence []Token
+		for _, word := range strings.Fields(s.Text()) {
+			tokenize(word, func(t Token) error {
+				sentence = append(sentence, t)
+				return nil
+			})
+		}
+		if len(sentence) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := f(sentence); err != nil {
+			return stopped(err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return wrapContext(ctx, err, "cannot read text")
+	}
+	return nil
+}
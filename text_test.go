@@ -0,0 +1,109 @@
+package corpus
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+)
+
+const testText = "D. Henrici Caſparis Abelii,\n\n  Wohlerfahrner Leib-Medicus\n"
+
+func TestTextTokens(t *testing.T) {
+	want := []Token{"D", ".", "Henrici", "Caſparis", "Abelii", ",",
+		"Wohlerfahrner", "Leib", "-", "Medicus"}
+	var tokener Tokener = NewText(strings.NewReader(testText))
+	var got []Token
+	if err := tokener.Tokens(func(t Token) { got = append(got, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestTextSentences(t *testing.T) {
+	want := [][]Token{
+		{"D", ".", "Henrici", "Caſparis", "Abelii", ","},
+		{"Wohlerfahrner", "Leib", "-", "Medicus"},
+	}
+	var got [][]Token
+	err := NewText(strings.NewReader(testText)).Sentences(func(s []Token) {
+		got = append(got, s)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestTextLongWord(t *testing.T) {
+	word := strings.Repeat("a", 1<<17)
+	text := "x " + word + " y\n"
+	want := []Token{"x", Token(word), "y"}
+	var got []Token
+	if err := NewText(strings.NewReader(text)).Tokens(func(t Token) {
+		got = append(got, t)
+	}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %d tokens; got %d", len(want), len(got))
+	}
+	got = nil
+	if err := NewText(strings.NewReader(text)).Sentences(func(s []Token) {
+		got = append(got, s...)
+	}); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %d tokens; got %d", len(want), len(got))
+	}
+}
+
+func TestTextStop(t *testing.T) {
+	var n int
+	err := NewText(strings.NewReader(testText)).SentencesContext(
+		context.Background(), func([]Token) error {
+			n++
+			return ErrStop
+		})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 call; got %d", n)
+	}
+}
+
+func TestTextErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"canceled", ctx, context.Canceled},
+		{"timeout", context.Background(), iotest.ErrTimeout},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			text := NewText(iotest.TimeoutReader(strings.NewReader(testText)))
+			err := text.TokensContext(tc.ctx, func(Token) error { return nil })
+			if got := errors.Cause(err); got != tc.want {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+			text = NewText(iotest.TimeoutReader(strings.NewReader(testText)))
+			err = text.SentencesContext(tc.ctx, func([]Token) error { return nil })
+			if got := errors.Cause(err); got != tc.want {
+				t.Fatalf("expected %v; got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,30 @@
 package corpus
 
-import "unicode"
+import (
+	"context"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ErrStop can be returned by callback functions to stop reading
+// without an error. Readers that get ErrStop from a callback
+// function stop reading and return nil.
+var ErrStop = errors.New("stop")
+
+// TokenFunc is the type of the callback function that is called
+// for each token. If it returns an error, reading stops and the
+// error is returned by the reader. If it returns ErrStop, reading
+// stops and the reader returns nil.
+type TokenFunc func(Token) error
+
+// TokenFuncOf converts a callback function that cannot fail to a TokenFunc.
+func TokenFuncOf(f func(Token)) TokenFunc {
+	return func(t Token) error {
+		f(t)
+		return nil
+	}
+}
 
 // Tokener defines the interface for things that read
 // a stream of tokens. If an error occurs, Err returns a non-nil value.
@@ -8,6 +32,58 @@ type Tokener interface {
 	Tokens(func(Token)) error
 }
 
+// ContextTokener defines the interface for things that read a stream
+// of tokens and can be canceled. Reading stops if the context is
+// canceled or if the callback function returns an error.
+type ContextTokener interface {
+	TokensContext(context.Context, TokenFunc) error
+}
+
+// TokenerOf converts a ContextTokener to a Tokener.
+func TokenerOf(t ContextTokener) Tokener {
+	if tt, ok := t.(Tokener); ok {
+		return tt
+	}
+	return tokener{t}
+}
+
+type tokener struct {
+	t ContextTokener
+}
+
+func (t tokener) Tokens(f func(Token)) error {
+	return t.t.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// ContextTokenerOf converts a Tokener to a ContextTokener. Since a
+// Tokener cannot be stopped, the returned ContextTokener ignores all
+// tokens after the context was canceled or the callback function
+// returned an error and reports the error after the Tokener is done.
+func ContextTokenerOf(t Tokener) ContextTokener {
+	if ct, ok := t.(ContextTokener); ok {
+		return ct
+	}
+	return contextTokener{t}
+}
+
+type contextTokener struct {
+	t Tokener
+}
+
+func (t contextTokener) TokensContext(ctx context.Context, f TokenFunc) error {
+	var ferr error
+	f = withContext(ctx, f)
+	err := t.t.Tokens(func(t Token) {
+		if ferr == nil {
+			ferr = f(t)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return stopped(ferr)
+}
+
 // TokenType represents the type of a token
 type TokenType int
 
@@ -59,3 +135,13 @@ func runeType(r rune) TokenType {
 func IsLetter(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsMark(r)
 }
+
+// Strings converts a slice of tokens to a slice of strings.
+// It can be used to add the tokens of a sentence to the n-gram maps.
+func Strings(ts []Token) []string {
+	strs := make([]string, len(ts))
+	for i, t := range ts {
+		strs[i] = string(t)
+	}
+	return strs
+}
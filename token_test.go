@@ -1,6 +1,12 @@
 package corpus
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestToken(t *testing.T) {
 	tests := []struct {
@@ -27,3 +33,78 @@ func TestToken(t *testing.T) {
 		})
 	}
 }
+
+func TestStrings(t *testing.T) {
+	got := new(Bigrams).Add(Strings([]Token{"a", "b", "c"})...)
+	if got.Get("a").Get("b") != 1 || got.Get("b").Get("c") != 1 {
+		t.Fatalf("invalid bigrams: %v", got)
+	}
+}
+
+type sliceTokener []Token
+
+func (ts sliceTokener) Tokens(f func(Token)) error {
+	for _, t := range ts {
+		f(t)
+	}
+	return nil
+}
+
+func TestContextTokenerOf(t *testing.T) {
+	tests := []struct {
+		stop int
+		err  error
+		want int
+	}{
+		{-1, nil, 3},
+		{1, ErrStop, 1},
+		{2, errClose, 2},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%d %v", tc.stop, tc.err), func(t *testing.T) {
+			var got int
+			ct := ContextTokenerOf(sliceTokener{"a", "b", "c"})
+			err := ct.TokensContext(context.Background(), func(Token) error {
+				got++
+				if got == tc.stop {
+					return tc.err
+				}
+				return nil
+			})
+			if tc.err != ErrStop && err != tc.err {
+				t.Fatalf("expected %v; got %v", tc.err, err)
+			}
+			if tc.err == ErrStop && err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %d; got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTokenerOf(t *testing.T) {
+	tei := NewTEI(strings.NewReader(`<TEI><text><p>a b</p></text></TEI>`))
+	if got := TokenerOf(tei); got != Tokener(tei) {
+		t.Fatalf("expected %v; got %v", tei, got)
+	}
+	var got []Token
+	err := TokenerOf(contextTokenerOnly{tei}).Tokens(func(t Token) {
+		got = append(got, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if want := []Token{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+type contextTokenerOnly struct {
+	t ContextTokener
+}
+
+func (t contextTokenerOnly) TokensContext(ctx context.Context, f TokenFunc) error {
+	return t.t.TokensContext(ctx, f)
+}
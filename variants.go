@@ -0,0 +1,114 @@
+package corpus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// Variants represents a dictionary that maps historical
+// spelling variants to their modern forms with their counts.
+type Variants struct {
+	variants Bigrams
+}
+
+// Add adds a historical variant with its modern form to the dictionary.
+func (v *Variants) Add(hist, modern string) *Variants {
+	v.variants.Add(hist, modern)
+	return v
+}
+
+// AddToken adds the original and corrected form of the annotated
+// token to the dictionary. Tokens without a correction are ignored.
+func (v *Variants) AddToken(t AnnotatedToken) *Variants {
+	if t.Correction == "" {
+		return v
+	}
+	return v.Add(string(t.Token), t.Correction)
+}
+
+// Append appends all variants of another dictionary to this.
+func (v *Variants) Append(o *Variants) *Variants {
+	if o == nil {
+		return v
+	}
+	v.variants.Append(&o.variants)
+	return v
+}
+
+// Total returns the total number of variants in the dictionary.
+func (v *Variants) Total() uint64 {
+	if v == nil {
+		return 0
+	}
+	return v.variants.Total()
+}
+
+// Len returns the number of different historical variants in the dictionary.
+func (v *Variants) Len() uint64 {
+	if v == nil {
+		return 0
+	}
+	return v.variants.Len()
+}
+
+// Get returns the modern forms for the given historical variant.
+func (v *Variants) Get(hist string) *Unigrams {
+	if v == nil {
+		return nil
+	}
+	return v.variants.Get(hist)
+}
+
+// Each calls the supplied callback function for each
+// historical variant in the dictionary.
+func (v *Variants) Each(f func(string, *Unigrams)) {
+	if v == nil {
+		return
+	}
+	v.variants.Each(f)
+}
+
+// MarshalJSON implements JSON marshaling.
+func (v *Variants) MarshalJSON() ([]byte, error) {
+	return v.variants.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling.
+func (v *Variants) UnmarshalJSON(bs []byte) error {
+	return v.variants.unmarshal(bs, json.Unmarshal)
+}
+
+// GobEncode implement gob marhsaling.
+func (v *Variants) GobEncode() ([]byte, error) {
+	return v.variants.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling.
+func (v *Variants) GobDecode(bs []byte) error {
+	return v.variants.unmarshal(bs, unmarshalGob)
+}
+
+// DTAReadVariantsAndClose is a convenience function that reads
+// all variants of a DTA file and closes the reader.
+func DTAReadVariantsAndClose(r io.ReadCloser, v *Variants) error {
+	return readAndClose(r, func(r io.Reader) error {
+		return DTAReadVariants(r, v)
+	})
+}
+
+// DTAReadVariants adds all orthographic corrections of
+// a DTA corpus file to the given dictionary.
+func DTAReadVariants(r io.Reader, v *Variants) error {
+	return DTAReadVariantsContext(context.Background(), r, v)
+}
+
+// DTAReadVariantsContext adds all orthographic corrections of a DTA
+// corpus file to the given dictionary. It handles cancellation and
+// errors in the same way as DTAReadTokensContext.
+func DTAReadVariantsContext(ctx context.Context, r io.Reader, v *Variants) error {
+	return DTAReadAnnotatedTokensContext(ctx, r, func(t AnnotatedToken) error {
+		v.AddToken(t)
+		return nil
+	})
+}
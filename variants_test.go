@@ -0,0 +1,100 @@
+package corpus
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAnnotatedTokenNormalized(t *testing.T) {
+	tests := []struct {
+		token AnnotatedToken
+		want  Token
+	}{
+		{AnnotatedToken{Token: "Caſparis"}, "Caſparis"},
+		{AnnotatedToken{Token: "Caſparis", Correction: "Casparis"}, "Casparis"},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.want), func(t *testing.T) {
+			if got := tc.token.Normalized(); got != tc.want {
+				t.Fatalf("expected %q; got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDTAReadVariants(t *testing.T) {
+	tests := []struct {
+		hist, modern string
+		count        uint64
+	}{
+		{"Caſparis", "Casparis", 2},
+		{"Wohlerfahrner", "Wohlerfahrener", 2},
+		{"Leib-Medicus", "Leib-Medikus", 2},
+		{"Henrici", "Henrici", 0},
+	}
+	v := new(Variants)
+	for i := 0; i < 2; i++ {
+		if err := DTAReadVariantsAndClose(openDTATestFile(t), v); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+	}
+	if got := v.Total(); got != 6 {
+		t.Fatalf("expected 6; got %d", got)
+	}
+	if got := v.Len(); got != 3 {
+		t.Fatalf("expected 3; got %d", got)
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %s", tc.hist, tc.modern), func(t *testing.T) {
+			if got := v.Get(tc.hist).Get(tc.modern); got != tc.count {
+				t.Fatalf("expected %d; got %d", tc.count, got)
+			}
+		})
+	}
+}
+
+func TestVariantsAppend(t *testing.T) {
+	a := new(Variants).Add("vnd", "und")
+	b := new(Variants).Add("vnd", "und").Add("thun", "tun")
+	a.Append(b).Append(nil)
+	if got := a.Get("vnd").Get("und"); got != 2 {
+		t.Fatalf("expected 2; got %d", got)
+	}
+	if got := a.Total(); got != 3 {
+		t.Fatalf("expected 3; got %d", got)
+	}
+}
+
+func TestVariantsMarshal(t *testing.T) {
+	v := new(Variants).Add("vnd", "und").Add("thun", "tun")
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(v); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		got := new(Variants)
+		if err := json.NewDecoder(buf).Decode(got); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if !reflect.DeepEqual(v, got) {
+			t.Fatalf("expected %v; got %v", v, got)
+		}
+	})
+	t.Run("gob", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := gob.NewEncoder(buf).Encode(v); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		got := new(Variants)
+		if err := gob.NewDecoder(buf).Decode(got); err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if !reflect.DeepEqual(v, got) {
+			t.Fatalf("expected %v; got %v", v, got)
+		}
+	})
+}
@@ -0,0 +1,578 @@
+package corpus
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// Vocabulary interns tokens to uint32 IDs. The IDs are assigned
+// consecutively starting with 0 in the order in which the tokens are
+// added. The zero value is an empty vocabulary ready to use. Lookup,
+// Word and Len treat a nil vocabulary as empty; ID, IDs and the
+// NewID... functions add tokens and need a non-nil vocabulary.
+type Vocabulary struct {
+	ids   map[string]uint32
+	words []string
+}
+
+// ID returns the ID of the given token. If the token is not yet part
+// of the vocabulary, it is added with a new ID. It panics if the
+// vocabulary is nil or if it already holds 2^32 tokens.
+func (v *Vocabulary) ID(w string) uint32 {
+	if v == nil {
+		panic("corpus: nil vocabulary")
+	}
+	if id, ok := v.ids[w]; ok {
+		return id
+	}
+	if uint64(len(v.words)) > math.MaxUint32 {
+		panic("corpus: vocabulary overflow")
+	}
+	if v.ids == nil {
+		v.ids = make(map[string]uint32)
+	}
+	id := uint32(len(v.words))
+	v.ids[w] = id
+	v.words = append(v.words, w)
+	return id
+}
+
+// IDs returns the IDs of the given tokens. Tokens that are not yet
+// part of the vocabulary are added (see ID).
+func (v *Vocabulary) IDs(ws ...string) []uint32 {
+	ids := make([]uint32, len(ws))
+	for i, w := range ws {
+		ids[i] = v.ID(w)
+	}
+	return ids
+}
+
+// Lookup returns the ID of the given token and true if the token is
+// part of the vocabulary.
+func (v *Vocabulary) Lookup(w string) (uint32, bool) {
+	if v == nil {
+		return 0, false
+	}
+	id, ok := v.ids[w]
+	return id, ok
+}
+
+// Word returns the token of the given ID. It returns the empty
+// string if the ID is not part of the vocabulary.
+func (v *Vocabulary) Word(id uint32) string {
+	if v == nil || int(id) >= len(v.words) {
+		return ""
+	}
+	return v.words[id]
+}
+
+// Len returns the number of tokens in the vocabulary.
+func (v *Vocabulary) Len() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.words)
+}
+
+type jsonVocabulary struct {
+	Words []string
+}
+
+// MarshalJSON implements JSON marshaling.
+func (v *Vocabulary) MarshalJSON() ([]byte, error) {
+	return v.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling.
+func (v *Vocabulary) UnmarshalJSON(bs []byte) error {
+	return v.unmarshal(bs, json.Unmarshal)
+}
+
+// GobEncode implement gob marhsaling.
+func (v *Vocabulary) GobEncode() ([]byte, error) {
+	return v.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling.
+func (v *Vocabulary) GobDecode(bs []byte) error {
+	return v.unmarshal(bs, unmarshalGob)
+}
+
+func (v *Vocabulary) marshal(f marshalFunc) ([]byte, error) {
+	return f(jsonVocabulary{Words: v.words})
+}
+
+func (v *Vocabulary) unmarshal(bs []byte, f unmarshalFunc) error {
+	var tmp jsonVocabulary
+	if err := f(bs, &tmp); err != nil {
+		return err
+	}
+	var res Vocabulary
+	for _, w := range tmp.Words {
+		if _, ok := res.ids[w]; ok {
+			return errors.Errorf("invalid vocabulary: duplicate token: %q", w)
+		}
+		res.ID(w)
+	}
+	*v = res
+	return nil
+}
+
+// IDUnigrams represents the absolute unigram frequencies of token IDs.
+type IDUnigrams struct {
+	unigrams map[uint32]uint64
+	total    uint64
+}
+
+// NewIDUnigrams converts the given unigrams to ID unigrams. The
+// tokens are interned in the given vocabulary.
+func NewIDUnigrams(v *Vocabulary, u *Unigrams) *IDUnigrams {
+	res := new(IDUnigrams)
+	u.Each(func(w string, n uint64) {
+		res.add(v.ID(w), n)
+	})
+	return res
+}
+
+// Unigrams converts the ID unigrams back to unigrams using the given
+// vocabulary.
+func (u *IDUnigrams) Unigrams(v *Vocabulary) *Unigrams {
+	res := new(Unigrams)
+	u.Each(func(id uint32, n uint64) {
+		res.addN(v.Word(id), n)
+	})
+	return res
+}
+
+// Add adds a range of unigrams to the map.
+func (u *IDUnigrams) Add(ids ...uint32) *IDUnigrams {
+	for _, id := range ids {
+		u.add(id, 1)
+	}
+	return u
+}
+
+func (u *IDUnigrams) add(id uint32, n uint64) {
+	if u.unigrams == nil {
+		u.unigrams = make(map[uint32]uint64)
+	}
+	u.unigrams[id] += n
+	u.total += n
+}
+
+// Get returns the count for the given unigram.
+func (u *IDUnigrams) Get(id uint32) uint64 {
+	if u == nil {
+		return 0
+	}
+	return u.unigrams[id]
+}
+
+// Total returns the total number of unigrams in the map.
+func (u *IDUnigrams) Total() uint64 {
+	if u == nil {
+		return 0
+	}
+	return u.total
+}
+
+// Len returns the total number different unigrams in the map.
+func (u *IDUnigrams) Len() uint64 {
+	if u == nil {
+		return 0
+	}
+	return uint64(len(u.unigrams))
+}
+
+// Each calls the supplied callback function for each
+// entry in the map.
+func (u *IDUnigrams) Each(f func(uint32, uint64)) {
+	if u == nil {
+		return
+	}
+	for k, v := range u.unigrams {
+		f(k, v)
+	}
+}
+
+type jsonIDUnigrams struct {
+	Total, Len uint64
+	Unigrams   map[uint32]uint64
+}
+
+// MarshalJSON implements JSON marshaling.
+func (u *IDUnigrams) MarshalJSON() ([]byte, error) {
+	return u.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling.
+func (u *IDUnigrams) UnmarshalJSON(bs []byte) error {
+	return u.unmarshal(bs, json.Unmarshal)
+}
+
+// GobEncode implement gob marhsaling.
+func (u *IDUnigrams) GobEncode() ([]byte, error) {
+	return u.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling.
+func (u *IDUnigrams) GobDecode(bs []byte) error {
+	return u.unmarshal(bs, unmarshalGob)
+}
+
+func (u *IDUnigrams) marshal(f marshalFunc) ([]byte, error) {
+	return f(jsonIDUnigrams{Total: u.Total(), Len: u.Len(), Unigrams: u.unigrams})
+}
+
+func (u *IDUnigrams) unmarshal(bs []byte, f unmarshalFunc) error {
+	var tmp jsonIDUnigrams
+	if err := f(bs, &tmp); err != nil {
+		return err
+	}
+	*u = IDUnigrams{}
+	for id, n := range tmp.Unigrams {
+		u.add(id, n)
+	}
+	return nil
+}
+
+// IDBigrams represents a map of token ID 2-grams. The two IDs of a
+// bigram are packed into one flat key.
+type IDBigrams struct {
+	bigrams map[uint64]uint64
+	heads   map[uint32]uint64 // number of bigrams for each first ID
+	total   uint64
+}
+
+// NewIDBigrams converts the given bigrams to ID bigrams. The tokens
+// are interned in the given vocabulary.
+func NewIDBigrams(v *Vocabulary, b *Bigrams) *IDBigrams {
+	res := new(IDBigrams)
+	b.Each(func(first string, u *Unigrams) {
+		id := v.ID(first)
+		u.Each(func(second string, n uint64) {
+			res.add(id, v.ID(second), n)
+		})
+	})
+	return res
+}
+
+// Bigrams converts the ID bigrams back to bigrams using the given
+// vocabulary.
+func (b *IDBigrams) Bigrams(v *Vocabulary) *Bigrams {
+	res := new(Bigrams)
+	b.Each(func(first, second uint32, n uint64) {
+		res.addN(v.Word(first), v.Word(second), n)
+	})
+	return res
+}
+
+// Add adds a range of bigrams to the map.
+func (b *IDBigrams) Add(ids ...uint32) *IDBigrams {
+	for i := 1; i < len(ids); i++ {
+		b.add(ids[i-1], ids[i], 1)
+	}
+	return b
+}
+
+func (b *IDBigrams) add(first, second uint32, n uint64) {
+	if b.bigrams == nil {
+		b.bigrams = make(map[uint64]uint64)
+		b.heads = make(map[uint32]uint64)
+	}
+	b.bigrams[uint64(first)<<32|uint64(second)] += n
+	b.heads[first] += n
+	b.total += n
+}
+
+// Get returns the count of the given bigram.
+func (b *IDBigrams) Get(first, second uint32) uint64 {
+	if b == nil {
+		return 0
+	}
+	return b.bigrams[uint64(first)<<32|uint64(second)]
+}
+
+// Head returns the number of bigrams with the given first ID.
+func (b *IDBigrams) Head(first uint32) uint64 {
+	if b == nil {
+		return 0
+	}
+	return b.heads[first]
+}
+
+// Total returns the total number of bigrams in the map.
+func (b *IDBigrams) Total() uint64 {
+	if b == nil {
+		return 0
+	}
+	return b.total
+}
+
+// Len returns the total number of different first IDs in the map.
+func (b *IDBigrams) Len() uint64 {
+	if b == nil {
+		return 0
+	}
+	return uint64(len(b.heads))
+}
+
+// Each calls the supplied callback function for each
+// entry in the map.
+func (b *IDBigrams) Each(f func(uint32, uint32, uint64)) {
+	if b == nil {
+		return
+	}
+	for k, v := range b.bigrams {
+		f(uint32(k>>32), uint32(k), v)
+	}
+}
+
+// jsonIDBigrams holds the bigrams as nested maps of the first and
+// the second ID.
+type jsonIDBigrams struct {
+	Total, Len uint64
+	Bigrams    map[uint32]map[uint32]uint64
+}
+
+// MarshalJSON implements JSON marshaling.
+func (b *IDBigrams) MarshalJSON() ([]byte, error) {
+	return b.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling.
+func (b *IDBigrams) UnmarshalJSON(bs []byte) error {
+	return b.unmarshal(bs, json.Unmarshal)
+}
+
+// GobEncode implement gob marhsaling.
+func (b *IDBigrams) GobEncode() ([]byte, error) {
+	return b.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling.
+func (b *IDBigrams) GobDecode(bs []byte) error {
+	return b.unmarshal(bs, unmarshalGob)
+}
+
+func (b *IDBigrams) marshal(f marshalFunc) ([]byte, error) {
+	tmp := jsonIDBigrams{Total: b.Total(), Len: b.Len()}
+	b.Each(func(first, second uint32, n uint64) {
+		if tmp.Bigrams == nil {
+			tmp.Bigrams = make(map[uint32]map[uint32]uint64)
+		}
+		if tmp.Bigrams[first] == nil {
+			tmp.Bigrams[first] = make(map[uint32]uint64)
+		}
+		tmp.Bigrams[first][second] = n
+	})
+	return f(tmp)
+}
+
+func (b *IDBigrams) unmarshal(bs []byte, f unmarshalFunc) error {
+	var tmp jsonIDBigrams
+	if err := f(bs, &tmp); err != nil {
+		return err
+	}
+	*b = IDBigrams{}
+	for first, m := range tmp.Bigrams {
+		for second, n := range m {
+			b.add(first, second, n)
+		}
+	}
+	return nil
+}
+
+// IDTrigrams represents a map of token ID 3-grams. The three IDs of
+// a trigram are used as one flat key.
+type IDTrigrams struct {
+	trigrams map[[3]uint32]uint64
+	heads    map[uint32]uint64 // number of trigrams for each first ID
+	total    uint64
+}
+
+// NewIDTrigrams converts the given trigrams to ID trigrams. The
+// tokens are interned in the given vocabulary.
+func NewIDTrigrams(v *Vocabulary, t *Trigrams) *IDTrigrams {
+	res := new(IDTrigrams)
+	t.Each(func(first string, b *Bigrams) {
+		id1 := v.ID(first)
+		b.Each(func(second string, u *Unigrams) {
+			id2 := v.ID(second)
+			u.Each(func(third string, n uint64) {
+				res.add([3]uint32{id1, id2, v.ID(third)}, n)
+			})
+		})
+	})
+	return res
+}
+
+// Trigrams converts the ID trigrams back to trigrams using the given
+// vocabulary.
+func (t *IDTrigrams) Trigrams(v *Vocabulary) *Trigrams {
+	res := new(Trigrams)
+	t.Each(func(first, second, third uint32, n uint64) {
+		res.addN(v.Word(first), v.Word(second), v.Word(third), n)
+	})
+	return res
+}
+
+// Add adds a range of trigrams to the map.
+func (t *IDTrigrams) Add(ids ...uint32) *IDTrigrams {
+	for i := 2; i < len(ids); i++ {
+		t.add([3]uint32{ids[i-2], ids[i-1], ids[i]}, 1)
+	}
+	return t
+}
+
+func (t *IDTrigrams) add(k [3]uint32, n uint64) {
+	if t.trigrams == nil {
+		t.trigrams = make(map[[3]uint32]uint64)
+		t.heads = make(map[uint32]uint64)
+	}
+	t.trigrams[k] += n
+	t.heads[k[0]] += n
+	t.total += n
+}
+
+// Get returns the count of the given trigram.
+func (t *IDTrigrams) Get(first, second, third uint32) uint64 {
+	if t == nil {
+		return 0
+	}
+	return t.trigrams[[3]uint32{first, second, third}]
+}
+
+// Head returns the number of trigrams with the given first ID.
+func (t *IDTrigrams) Head(first uint32) uint64 {
+	if t == nil {
+		return 0
+	}
+	return t.heads[first]
+}
+
+// Total returns the total number of trigrams in the map.
+func (t *IDTrigrams) Total() uint64 {
+	if t == nil {
+		return 0
+	}
+	return t.total
+}
+
+// Len returns the total number of different first IDs in the map.
+func (t *IDTrigrams) Len() uint64 {
+	if t == nil {
+		return 0
+	}
+	return uint64(len(t.heads))
+}
+
+// Each calls the supplied callback function for each
+// entry in the map.
+func (t *IDTrigrams) Each(f func(uint32, uint32, uint32, uint64)) {
+	if t == nil {
+		return
+	}
+	for k, v := range t.trigrams {
+		f(k[0], k[1], k[2], v)
+	}
+}
+
+// jsonIDTrigrams holds the trigrams as nested maps of the first, the
+// second and the third ID.
+type jsonIDTrigrams struct {
+	Total, Len uint64
+	Trigrams   map[uint32]map[uint32]map[uint32]uint64
+}
+
+// MarshalJSON implements JSON marshaling.
+func (t *IDTrigrams) MarshalJSON() ([]byte, error) {
+	return t.marshal(json.Marshal)
+}
+
+// UnmarshalJSON implements JSON unmarshaling.
+func (t *IDTrigrams) UnmarshalJSON(bs []byte) error {
+	return t.unmarshal(bs, json.Unmarshal)
+}
+
+// GobEncode implement gob marhsaling.
+func (t *IDTrigrams) GobEncode() ([]byte, error) {
+	return t.marshal(marshalGob)
+}
+
+// GobDecode implements gob unmarshaling.
+func (t *IDTrigrams) GobDecode(bs []byte) error {
+	return t.unmarshal(bs, unmarshalGob)
+}
+
+func (t *IDTrigrams) marshal(f marshalFunc) ([]byte, error) {
+	tmp := jsonIDTrigrams{Total: t.Total(), Len: t.Len()}
+	t.Each(func(first, second, third uint32, n uint64) {
+		if tmp.Trigrams == nil {
+			tmp.Trigrams = make(map[uint32]map[uint32]map[uint32]uint64)
+		}
+		if tmp.Trigrams[first] == nil {
+			tmp.Trigrams[first] = make(map[uint32]map[uint32]uint64)
+		}
+		if tmp.Trigrams[first][second] == nil {
+			tmp.Trigrams[first][second] = make(map[uint32]uint64)
+		}
+		tmp.Trigrams[first][second][third] = n
+	})
+	return f(tmp)
+}
+
+func (t *IDTrigrams) unmarshal(bs []byte, f unmarshalFunc) error {
+	var tmp jsonIDTrigrams
+	if err := f(bs, &tmp); err != nil {
+		return err
+	}
+	*t = IDTrigrams{}
+	for first, m := range tmp.Trigrams {
+		for second, m := range m {
+			for third, n := range m {
+				t.add([3]uint32{first, second, third}, n)
+			}
+		}
+	}
+	return nil
+}
+
+// addN adds the unigram n times.
+func (u *Unigrams) addN(w string, n uint64) {
+	if u.unigrams == nil {
+		u.unigrams = make(map[string]uint64)
+	}
+	u.unigrams[w] += n
+	u.total += n
+}
+
+// addN adds the bigram n times.
+func (b *Bigrams) addN(first, second string, n uint64) {
+	if b.bigrams == nil {
+		b.bigrams = make(map[string]*Unigrams)
+	}
+	u, ok := b.bigrams[first]
+	if !ok {
+		u = new(Unigrams)
+		b.bigrams[first] = u
+	}
+	u.addN(second, n)
+	b.total += n
+}
+
+// addN adds the trigram n times.
+func (t *Trigrams) addN(first, second, third string, n uint64) {
+	if t.trigrams == nil {
+		t.trigrams = make(map[string]*Bigrams)
+	}
+	b, ok := t.trigrams[first]
+	if !ok {
+		b = new(Bigrams)
+		t.trigrams[first] = b
+	}
+	b.addN(second, third, n)
+	t.total += n
+}
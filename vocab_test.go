@@ -0,0 +1,229 @@
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestVocabulary(t *testing.T) {
+	var v Vocabulary
+	if got := v.IDs("a", "b", "a", "c"); !reflect.DeepEqual(got, []uint32{0, 1, 0, 2}) {
+		t.Fatalf("expected [0 1 0 2]; got %v", got)
+	}
+	tests := []struct {
+		word string
+		id   uint32
+		ok   bool
+	}{
+		{"a", 0, true},
+		{"c", 2, true},
+		{"d", 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.word, func(t *testing.T) {
+			id, ok := v.Lookup(tc.word)
+			if id != tc.id || ok != tc.ok {
+				t.Fatalf("expected %d %t; got %d %t", tc.id, tc.ok, id, ok)
+			}
+			if ok && v.Word(id) != tc.word {
+				t.Fatalf("expected %s; got %s", tc.word, v.Word(id))
+			}
+		})
+	}
+	if got := v.Len(); got != 3 {
+		t.Fatalf("expected 3; got %d", got)
+	}
+	if got := v.Word(42); got != "" {
+		t.Fatalf("expected empty string; got %q", got)
+	}
+}
+
+func TestVocabularyMarshal(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(interface{}) ([]byte, error)
+		unmarshal func([]byte, interface{}) error
+	}{
+		{"json", json.Marshal, json.Unmarshal},
+		{"gob", marshalGob, unmarshalGob},
+	}
+	want := new(Vocabulary)
+	want.IDs("a", "b", "Größe")
+	for _, c := range codecs {
+		t.Run(c.name, func(t *testing.T) {
+			bs, err := c.marshal(want)
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			got := new(Vocabulary)
+			if err := c.unmarshal(bs, got); err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %v; got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestVocabularyUnmarshalDuplicates(t *testing.T) {
+	var v Vocabulary
+	v.IDs("x", "y")
+	if err := json.Unmarshal([]byte(`{"Words":["a","b","a"]}`), &v); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+	if _, ok := v.Lookup("a"); ok || v.Len() != 2 {
+		t.Fatalf("expected an unchanged vocabulary; got %v", v.words)
+	}
+}
+
+func TestIDUnigramsUnmarshalTotal(t *testing.T) {
+	var u IDUnigrams
+	if err := json.Unmarshal([]byte(`{"Total":42,"Len":2,"Unigrams":{"0":2,"1":3}}`), &u); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if got := u.Total(); got != 5 {
+		t.Fatalf("expected 5; got %d", got)
+	}
+}
+
+func TestVocabularyNil(t *testing.T) {
+	var v *Vocabulary
+	if _, ok := v.Lookup("a"); ok || v.Word(0) != "" || v.Len() != 0 {
+		t.Fatalf("expected an empty vocabulary")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected a panic; got nil")
+		}
+	}()
+	v.ID("a")
+}
+
+func TestIDNGramsMarshal(t *testing.T) {
+	codecs := []struct {
+		name      string
+		marshal   func(interface{}) ([]byte, error)
+		unmarshal func([]byte, interface{}) error
+	}{
+		{"json", json.Marshal, json.Unmarshal},
+		{"gob", marshalGob, unmarshalGob},
+	}
+	var v Vocabulary
+	ids := v.IDs("a", "b", "c", "a", "b", "d", "a")
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{"unigrams", new(IDUnigrams).Add(ids...)},
+		{"bigrams", new(IDBigrams).Add(ids...)},
+		{"trigrams", new(IDTrigrams).Add(ids...)},
+	}
+	for _, c := range codecs {
+		for _, tc := range tests {
+			t.Run(c.name+" "+tc.name, func(t *testing.T) {
+				bs, err := c.marshal(tc.data)
+				if err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+				got := reflect.New(reflect.TypeOf(tc.data).Elem()).Interface()
+				if err := c.unmarshal(bs, got); err != nil {
+					t.Fatalf("got error: %v", err)
+				}
+				if !reflect.DeepEqual(got, tc.data) {
+					t.Fatalf("expected %v; got %v", tc.data, got)
+				}
+			})
+		}
+	}
+}
+
+func TestIDNGrams(t *testing.T) {
+	ts := []string{"a", "b", "c", "a", "b", "d", "a"}
+	var v Vocabulary
+	ids := v.IDs(ts...)
+	u := new(IDUnigrams).Add(ids...)
+	b := new(IDBigrams).Add(ids...)
+	tr := new(IDTrigrams).Add(ids...)
+	tests := []struct {
+		name      string
+		got, want uint64
+	}{
+		{"unigram", u.Get(ids[0]), 3},
+		{"unigram total", u.Total(), 7},
+		{"unigram len", u.Len(), 4},
+		{"bigram", b.Get(ids[0], ids[1]), 2},
+		{"bigram head", b.Head(ids[0]), 2},
+		{"bigram total", b.Total(), 6},
+		{"bigram len", b.Len(), 4},
+		{"trigram", tr.Get(ids[0], ids[1], ids[2]), 1},
+		{"trigram head", tr.Head(ids[1]), 2},
+		{"trigram total", tr.Total(), 5},
+		{"trigram len", tr.Len(), 3},
+		{"missing", tr.Get(ids[2], ids[1], ids[0]), 0},
+		{"nil", (*IDBigrams)(nil).Get(0, 0), 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Fatalf("expected %d; got %d", tc.want, tc.got)
+			}
+		})
+	}
+	if got, want := u.Unigrams(&v), new(Unigrams).Add(ts...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if got, want := b.Bigrams(&v), new(Bigrams).Add(ts...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+	if got, want := tr.Trigrams(&v), new(Trigrams).Add(ts...); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestIDNGramsConversion(t *testing.T) {
+	ts := []string{"a", "b", "c", "a", "b", "d", "a"}
+	u := new(Unigrams).Add(ts...)
+	b := new(Bigrams).Add(ts...)
+	tr := new(Trigrams).Add(ts...)
+	var v Vocabulary
+	if got := NewIDUnigrams(&v, u).Unigrams(&v); !reflect.DeepEqual(got, u) {
+		t.Fatalf("expected %v; got %v", u, got)
+	}
+	if got := NewIDBigrams(&v, b).Bigrams(&v); !reflect.DeepEqual(got, b) {
+		t.Fatalf("expected %v; got %v", b, got)
+	}
+	if got := NewIDTrigrams(&v, tr).Trigrams(&v); !reflect.DeepEqual(got, tr) {
+		t.Fatalf("expected %v; got %v", tr, got)
+	}
+	if got := v.Len(); got != 4 {
+		t.Fatalf("expected 4; got %d", got)
+	}
+}
+
+func benchmarkTokens(n int) []string {
+	ts := make([]string, n)
+	for i := range ts {
+		ts[i] = fmt.Sprintf("token%d", (i*7919)%(n/10+1))
+	}
+	return ts
+}
+
+func BenchmarkTrigramsAdd(b *testing.B) {
+	ts := benchmarkTokens(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		new(Trigrams).Add(ts...)
+	}
+}
+
+func BenchmarkIDTrigramsAdd(b *testing.B) {
+	ts := benchmarkTokens(100000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v Vocabulary
+		new(IDTrigrams).Add(v.IDs(ts...)...)
+	}
+}
@@ -0,0 +1,227 @@
+package corpus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	vrtEscaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	vrtUnescaper = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`)
+)
+
+// VRTWriteDocument writes the sentences as one document in the
+// vertical (VRT) format of the IMS Open Corpus Workbench. The
+// positional attributes are word, pos, lemma and norm (the normalized
+// form of the token). Missing annotations are written as `_`. The
+// document's title, author, year, genre and subgenre structural
+// attributes are taken from the given metadata (which can be nil).
+// An error is returned if any of the values contains a tab or a
+// newline.
+func VRTWriteDocument(w io.Writer, id string, m *DTAMetadata, ss []Sentence) error {
+	attrs := []string{"id", id}
+	if m != nil {
+		var authors []string
+		for _, a := range m.Authors {
+			authors = append(authors, a.String())
+		}
+		var year string
+		if m.Year() != 0 {
+			year = strconv.Itoa(m.Year())
+		}
+		attrs = append(attrs, "title", m.Title,
+			"author", strings.Join(authors, "; "),
+			"year", year,
+			"genre", m.Genre,
+			"subgenre", m.SubGenre)
+	}
+	if err := checkVRT(attrs...); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("<doc")
+	for i := 1; i < len(attrs); i += 2 {
+		bw.WriteString(vrtAttr(attrs[i-1], attrs[i]))
+	}
+	bw.WriteString(">\n")
+	for _, s := range ss {
+		if err := checkVRT("sentence id", s.ID); err != nil {
+			return err
+		}
+		bw.WriteString("<s" + vrtAttr("id", s.ID) + ">\n")
+		for _, t := range s.Tokens {
+			if err := checkVRT("word", string(t.Token), "pos", t.POS,
+				"lemma", t.Lemma, "norm", string(t.Normalized())); err != nil {
+				return err
+			}
+			fmt.Fprintf(bw, "%s\t%s\t%s\t%s\n",
+				vrtField(string(t.Token)), vrtField(t.POS),
+				vrtField(t.Lemma), vrtField(string(t.Normalized())))
+		}
+		bw.WriteString("</s>\n")
+	}
+	bw.WriteString("</doc>\n")
+	if err := bw.Flush(); err != nil {
+		return errors.Wrapf(err, "cannot write vrt file")
+	}
+	return nil
+}
+
+// checkVRT checks pairs of attribute names and values. It returns an
+// error if a value contains a tab or a newline.
+func checkVRT(kvs ...string) error {
+	for i := 1; i < len(kvs); i += 2 {
+		if strings.ContainsAny(kvs[i], "\t\r\n") {
+			return errors.Errorf("cannot write vrt file: invalid %s: %q", kvs[i-1], kvs[i])
+		}
+	}
+	return nil
+}
+
+func vrtAttr(key, val string) string {
+	if val == "" {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, key, vrtEscaper.Replace(val))
+}
+
+func vrtField(str string) string {
+	if str == "" {
+		return "_"
+	}
+	return vrtEscaper.Replace(str)
+}
+
+func vrtValue(str string) string {
+	if str == "_" {
+		return ""
+	}
+	return vrtUnescaper.Replace(str)
+}
+
+// VRT reads tokens from files in the vertical (VRT) format.
+type VRT struct {
+	r io.Reader
+}
+
+// NewVRT creates a new VRT reader that reads from the given reader.
+func NewVRT(r io.Reader) *VRT {
+	return &VRT{r: r}
+}
+
+// Tokens implements the Tokener interface. The tokens of the word
+// attribute are split in the same way as DTAReadTokens splits them.
+func (v *VRT) Tokens(f func(Token)) error {
+	return v.TokensContext(context.Background(), TokenFuncOf(f))
+}
+
+// TokensContext implements the ContextTokener interface.
+func (v *VRT) TokensContext(ctx context.Context, f TokenFunc) error {
+	return v.SentencesContext(ctx, func(s Sentence) error {
+		for _, t := range s.Tokens {
+			if err := tokenize(string(t.Token), f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Sentences reads all sentences. The positional attributes are read
+// in the order that VRTWriteDocument writes them. If the norm attribute
+// differs from the word, it is read as correction. Sentences without
+// an id attribute are numbered s1, s2, ...
+func (v *VRT) Sentences(f func(Sentence)) error {
+	return v.SentencesContext(context.Background(), func(s Sentence) error {
+		f(s)
+		return nil
+	})
+}
+
+// SentencesContext reads all sentences. It handles cancellation and
+// errors in the same way as DTAReadTokensContext.
+func (v *VRT) SentencesContext(ctx context.Context, f func(Sentence) error) error {
+	s := bufio.NewScanner(contextReader{ctx: ctx, r: v.r})
+	s.Buffer(nil, maxLineLength)
+	var n int
+	var sentence Sentence
+	flush := func() error {
+		if len(sentence.Tokens) == 0 {
+			sentence = Sentence{}
+			return nil
+		}
+		n++
+		if sentence.ID == "" {
+			sentence.ID = fmt.Sprintf("s%d", n)
+		}
+		for i := range sentence.Tokens {
+			sentence.Tokens[i].Sentence = sentence.ID
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := f(sentence)
+		sentence = Sentence{}
+		return err
+	}
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+		case strings.HasPrefix(line, "<s>") || strings.HasPrefix(line, "<s "):
+			if err := flush(); err != nil {
+				return stopped(err)
+			}
+			sentence.ID = vrtStructAttr(line, "id")
+		case strings.HasPrefix(line, "</s>"), strings.HasPrefix(line, "<doc"),
+			strings.HasPrefix(line, "</doc>"):
+			if err := flush(); err != nil {
+				return stopped(err)
+			}
+		case strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">"):
+			// ignore other structural attributes
+		default:
+			sentence.Tokens = append(sentence.Tokens, parseVRTToken(line))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return wrapContext(ctx, err, "invalid vrt file")
+	}
+	return stopped(flush())
+}
+
+func parseVRTToken(line string) AnnotatedToken {
+	cols := strings.Split(line, "\t")
+	for len(cols) < 4 {
+		cols = append(cols, "_")
+	}
+	t := AnnotatedToken{
+		Token: Token(vrtUnescaper.Replace(cols[0])),
+		POS:   vrtValue(cols[1]),
+		Lemma: vrtValue(cols[2]),
+	}
+	if norm := vrtValue(cols[3]); norm != "" && norm != string(t.Token) {
+		t.Correction = norm
+	}
+	return t
+}
+
+func vrtStructAttr(line, key string) string {
+	prefix := " " + key + `="`
+	i := strings.Index(line, prefix)
+	if i < 0 {
+		return ""
+	}
+	val := line[i+len(prefix):]
+	j := strings.Index(val, `"`)
+	if j < 0 {
+		return ""
+	}
+	return vrtUnescaper.Replace(val[:j])
+}
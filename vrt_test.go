@@ -0,0 +1,109 @@
+package corpus
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVRTWriteDocument(t *testing.T) {
+	m := &DTAMetadata{
+		Title:   `"Leib-Medicus" & more`,
+		Authors: []DTAPerson{{Surname: "Abel", Forename: "Heinrich Caspar"}},
+		Date:    "1699",
+		Genre:   "Fachtext",
+	}
+	ss := []Sentence{{ID: "s1", Tokens: []AnnotatedToken{
+		{Token: "Caſparis", POS: "NE", Lemma: "Casparis", Correction: "Casparis"},
+		{Token: "<"},
+	}}}
+	want := `<doc id="d1" title="&quot;Leib-Medicus&quot; &amp; more"` +
+		` author="Abel, Heinrich Caspar" year="1699" genre="Fachtext">` + "\n" +
+		`<s id="s1">` + "\n" +
+		"Caſparis\tNE\tCasparis\tCasparis\n" +
+		"&lt;\t_\t_\t&lt;\n" +
+		"</s>\n</doc>\n"
+	buf := &bytes.Buffer{}
+	if err := VRTWriteDocument(buf, "d1", m, ss); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("expected %q; got %q", want, got)
+	}
+	if err := VRTWriteDocument(errWriter{}, "d1", nil, ss); err == nil {
+		t.Fatalf("expected an error; got nil")
+	}
+}
+
+func TestVRTWriteDocumentInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *DTAMetadata
+		s    Sentence
+	}{
+		{"title", &DTAMetadata{Title: "a\nb"}, Sentence{}},
+		{"sentence id", nil, Sentence{ID: "s\t1"}},
+		{"word", nil, Sentence{Tokens: []AnnotatedToken{{Token: "a\tb"}}}},
+		{"lemma", nil, Sentence{Tokens: []AnnotatedToken{{Token: "a", Lemma: "a\r\n"}}}},
+		{"norm", nil, Sentence{Tokens: []AnnotatedToken{{Token: "a", Correction: "a\nb"}}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := VRTWriteDocument(buf, "d1", tc.m, []Sentence{tc.s}); err == nil {
+				t.Fatalf("expected an error; got nil")
+			}
+		})
+	}
+}
+
+func TestVRTRoundTrip(t *testing.T) {
+	r := openDTATestFile(t)
+	defer r.Close()
+	var want []AnnotatedToken
+	err := DTAReadAnnotatedTokensAndClose(openDTATestFile(t), func(t AnnotatedToken) {
+		t.ID = ""
+		if t.Sentence == "" {
+			t.Sentence = "s2"
+		}
+		want = append(want, t)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	m, err := DTAReadMetadata(r)
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := VRTWriteDocument(buf, "abel_leibmedicus_1699", m, SentencesOf(want)); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), `<doc id="abel_leibmedicus_1699" title="Wohlerfahrner`) {
+		t.Fatalf("invalid doc header: %s", buf.String())
+	}
+	var got []AnnotatedToken
+	err = NewVRT(buf).Sentences(func(s Sentence) {
+		got = append(got, s.Tokens...)
+	})
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}
+
+func TestVRTTokens(t *testing.T) {
+	vrt := "<doc>\n<text>\nD.\tNE\n</text>\nHenrici\n</doc>\n"
+	want := []Token{"D", ".", "Henrici"}
+	var tokener Tokener = NewVRT(strings.NewReader(vrt))
+	var got []Token
+	if err := tokener.Tokens(func(t Token) { got = append(got, t) }); err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v; got %v", want, got)
+	}
+}